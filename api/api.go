@@ -22,23 +22,43 @@ THE SOFTWARE.
 package api
 
 import (
+	"context"
+	"time"
+
 	"github.com/tnagatomi/gh-fuda/option"
 )
 
-// APIClient is an interface for the API client
+// APIClient is an interface for the API client. Canceling the context of a
+// call aborts its queries and its waits between retries, but a mutation
+// already sent is waited for, so that whether it was made is known.
 type APIClient interface {
 	// Repository label operations
-	CreateLabel(label option.Label, repo option.Repo) error
-	UpdateLabel(label option.Label, repo option.Repo) error
-	DeleteLabel(label string, repo option.Repo) error
-	ListLabels(repo option.Repo) ([]option.Label, error)
+	CreateLabel(ctx context.Context, label option.Label, repo option.Repo) error
+	UpdateLabel(ctx context.Context, label option.Label, repo option.Repo) error
+	DeleteLabel(ctx context.Context, label string, repo option.Repo) error
+	// ApplyLabelChanges makes several changes in one request, returning the
+	// error of each change, nil if it succeeded
+	ApplyLabelChanges(ctx context.Context, repo option.Repo, changes []option.LabelChange) []error
+	ListLabels(ctx context.Context, repo option.Repo) ([]option.Label, error)
+	// ListLabelsOfRepos lists the labels of several repositories in one
+	// request, returning the labels and the error of each repository
+	ListLabelsOfRepos(ctx context.Context, repos []option.Repo) ([][]option.Label, []error)
+	GetLabelUsage(ctx context.Context, repo option.Repo, labelName string) (option.LabelUsage, error)
 
 	// Labelable operations (for merge command)
-	SearchLabelables(repo option.Repo, labelName string) ([]option.Labelable, error)
-	AddLabelsToLabelable(labelableID option.GraphQLID, labelIDs []option.GraphQLID) error
-	RemoveLabelsFromLabelable(labelableID option.GraphQLID, labelIDs []option.GraphQLID) error
+	SearchLabelables(ctx context.Context, repo option.Repo, labelName string) ([]option.Labelable, error)
+	AddLabelsToLabelable(ctx context.Context, labelableID option.GraphQLID, labelIDs []option.GraphQLID) error
+	RemoveLabelsFromLabelable(ctx context.Context, labelableID option.GraphQLID, labelIDs []option.GraphQLID) error
+
+	// Repository selection
+	ListRepositories(ctx context.Context, owner string) ([]option.Repository, error)
+	SearchRepositories(ctx context.Context, query string) ([]option.Repository, error)
+
+	// RateLimitDelay returns how long to wait before starting more work, to
+	// stay within the rate limit
+	RateLimitDelay() time.Duration
 
 	// Helper methods for GraphQL operations
-	GetRepositoryID(repo option.Repo) (option.GraphQLID, error)
-	GetLabelID(repo option.Repo, labelName string) (option.GraphQLID, error)
+	GetRepositoryID(ctx context.Context, repo option.Repo) (option.GraphQLID, error)
+	GetLabelID(ctx context.Context, repo option.Repo, labelName string) (option.GraphQLID, error)
 }
@@ -0,0 +1,295 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	graphql "github.com/cli/shurcooL-graphql"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+// ApplyLabelChanges makes changes to the labels of a repository in a single
+// request, as one aliased mutation per change. GitHub runs the mutations in
+// order and each one succeeds or fails on its own, so the error of each change
+// is returned, nil if it succeeded. The IDs the mutations need are looked up
+// beforehand with a single query too.
+func (g *GraphQLAPI) ApplyLabelChanges(ctx context.Context, repo option.Repo, changes []option.LabelChange) []error {
+	errs := make([]error, len(changes))
+	if len(changes) == 0 {
+		return errs
+	}
+
+	repoID, labelIDs, err := g.labelIDs(ctx, repo, changes)
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	var params, fields []string
+	variables := make(map[string]any)
+	aliases := make(map[string]int)
+	for i, change := range changes {
+		input := fmt.Sprintf("input%d", i)
+		var mutation, inputType string
+		switch change.Type {
+		case option.LabelChangeCreate:
+			mutation, inputType = "createLabel", "CreateLabelInput"
+			variables[input] = CreateLabelInput{
+				RepositoryID: string(repoID),
+				Name:         change.Label.Name,
+				Color:        change.Label.Color,
+				Description:  change.Label.Description,
+			}
+		case option.LabelChangeUpdate, option.LabelChangeDelete:
+			labelID, ok := labelIDs[i]
+			if !ok {
+				errs[i] = &NotFoundError{ResourceType: ResourceTypeLabel}
+				continue
+			}
+			if change.Type == option.LabelChangeUpdate {
+				mutation, inputType = "updateLabel", "UpdateLabelInput"
+				variables[input] = updateLabelInput(labelID, change.Label)
+			} else {
+				mutation, inputType = "deleteLabel", "DeleteLabelInput"
+				variables[input] = DeleteLabelInput{ID: string(labelID)}
+			}
+		default:
+			errs[i] = fmt.Errorf("unknown label change %q", change.Type)
+			continue
+		}
+
+		alias := fmt.Sprintf("change%d", i)
+		aliases[alias] = i
+		params = append(params, fmt.Sprintf("$%s: %s!", input, inputType))
+		fields = append(fields, fmt.Sprintf("%s: %s(input: $%s) { clientMutationId }", alias, mutation, input))
+	}
+	if len(aliases) == 0 {
+		return errs
+	}
+
+	query := fmt.Sprintf("mutation ApplyLabelChanges(%s) {\n%s\n}", strings.Join(params, ", "), strings.Join(fields, "\n"))
+
+	// Creating and deleting are not idempotent, so like mutateNonIdempotent
+	// this only retries when the whole request was rate limited
+	cfg := g.retry
+	cfg.retryable = IsRateLimit
+	err = withRetry(ctx, func() error {
+		// Once sent, the mutations run to the end even if ctx is canceled,
+		// so that the outcome of each change is known
+		err := g.client.DoWithContext(context.WithoutCancel(ctx), query, variables, nil)
+		var gqlErr *api.GraphQLError
+		if errors.As(err, &gqlErr) {
+			if changeErrs, ok := aliasErrors(gqlErr, aliases, ResourceTypeLabel); ok {
+				for i, changeErr := range changeErrs {
+					errs[i] = changeErr
+				}
+				return nil
+			}
+		}
+		return wrapGraphQLError(err, ResourceTypeLabel)
+	}, cfg)
+	if err != nil {
+		for _, i := range aliases {
+			errs[i] = err
+		}
+	}
+
+	return errs
+}
+
+// aliasErrors maps the errors of an aliased request to the items (changes or
+// repositories) by the alias their path starts with, converting them with
+// wrapGraphQLError. It returns false if an error is not about any item, as
+// when the whole request failed.
+func aliasErrors(gqlErr *api.GraphQLError, aliases map[string]int, resourceType ResourceType) (map[int]error, bool) {
+	items := make(map[int][]api.GraphQLErrorItem)
+	for _, item := range gqlErr.Errors {
+		if len(item.Path) == 0 {
+			return nil, false
+		}
+		alias, _ := item.Path[0].(string)
+		i, ok := aliases[alias]
+		if !ok {
+			return nil, false
+		}
+		items[i] = append(items[i], item)
+	}
+
+	errs := make(map[int]error, len(items))
+	for i, itemErrors := range items {
+		errs[i] = wrapGraphQLError(&api.GraphQLError{Errors: itemErrors}, resourceType)
+	}
+	return errs, true
+}
+
+// labelIDs looks up the ID of the repository and those of the labels to
+// update or delete, by index of the change, with one aliased query. Labels
+// that do not exist have no ID.
+func (g *GraphQLAPI) labelIDs(ctx context.Context, repo option.Repo, changes []option.LabelChange) (option.GraphQLID, map[int]option.GraphQLID, error) {
+	var params, fields []string
+	variables := map[string]any{
+		"owner": repo.Owner,
+		"name":  repo.Repo,
+	}
+	for i, change := range changes {
+		if change.Type == option.LabelChangeCreate {
+			continue
+		}
+		alias := fmt.Sprintf("label%d", i)
+		variables[alias] = change.Label.Name
+		params = append(params, fmt.Sprintf(", $%s: String!", alias))
+		fields = append(fields, fmt.Sprintf("%s: label(name: $%s) { id }", alias, alias))
+	}
+	if len(fields) == 0 {
+		repoID, err := g.GetRepositoryID(ctx, repo)
+		return repoID, nil, err
+	}
+
+	query := fmt.Sprintf("query LabelIDs($owner: String!, $name: String!%s) {\nrepository(owner: $owner, name: $name) {\nid\n%s\n}\n%s\n}", strings.Join(params, ""), strings.Join(fields, "\n"), rateLimitSelection)
+
+	var response struct {
+		Repository map[string]json.RawMessage
+		RateLimit  rateLimitFragment
+	}
+	err := withRetry(ctx, func() error {
+		return wrapGraphQLError(g.client.DoWithContext(ctx, query, variables, &response), ResourceTypeRepository)
+	}, g.retry)
+	if err != nil {
+		return "", nil, err
+	}
+	g.budget.observeQuery(response.RateLimit)
+	if response.Repository == nil {
+		return "", nil, &NotFoundError{ResourceType: ResourceTypeRepository}
+	}
+
+	var repoID string
+	if err := json.Unmarshal(response.Repository["id"], &repoID); err != nil {
+		return "", nil, fmt.Errorf("failed to parse repository ID: %v", err)
+	}
+	g.repoIDMu.Lock()
+	g.repoIDCache[repo.String()] = option.GraphQLID(repoID)
+	g.repoIDMu.Unlock()
+
+	labelIDs := make(map[int]option.GraphQLID)
+	for i, change := range changes {
+		if change.Type == option.LabelChangeCreate {
+			continue
+		}
+		var label *struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(response.Repository[fmt.Sprintf("label%d", i)], &label); err != nil {
+			return "", nil, fmt.Errorf("failed to parse label ID: %v", err)
+		}
+		if label != nil && label.ID != "" {
+			labelIDs[i] = option.GraphQLID(label.ID)
+		}
+	}
+
+	return option.GraphQLID(repoID), labelIDs, nil
+}
+
+// ListLabelsOfRepos fetches the labels of several repositories in one query,
+// with one aliased repository field per repository. Only repositories with
+// more labels than fit in a page need more queries, for their next pages. The
+// labels and the error of each repository are returned.
+func (g *GraphQLAPI) ListLabelsOfRepos(ctx context.Context, repos []option.Repo) ([][]option.Label, []error) {
+	labels := make([][]option.Label, len(repos))
+	errs := make([]error, len(repos))
+	if len(repos) == 0 {
+		return labels, errs
+	}
+
+	var params, fields []string
+	variables := make(map[string]any)
+	aliases := make(map[string]int)
+	for i, repo := range repos {
+		alias := fmt.Sprintf("repo%d", i)
+		aliases[alias] = i
+		variables[fmt.Sprintf("owner%d", i)] = repo.Owner
+		variables[fmt.Sprintf("name%d", i)] = repo.Repo
+		params = append(params, fmt.Sprintf("$owner%d: String!, $name%d: String!", i, i))
+		fields = append(fields, fmt.Sprintf("%s: repository(owner: $owner%d, name: $name%d) {\nlabels(first: 100) {\nnodes { name color description }\npageInfo { hasNextPage endCursor }\n}\n}", alias, i, i))
+	}
+
+	query := fmt.Sprintf("query RepositoriesLabels(%s) {\n%s\n%s\n}", strings.Join(params, ", "), strings.Join(fields, "\n"), rateLimitSelection)
+
+	var response map[string]json.RawMessage
+	err := withRetry(ctx, func() error {
+		err := g.client.DoWithContext(ctx, query, variables, &response)
+		var gqlErr *api.GraphQLError
+		if errors.As(err, &gqlErr) {
+			if repoErrs, ok := aliasErrors(gqlErr, aliases, ResourceTypeRepository); ok {
+				for i, repoErr := range repoErrs {
+					errs[i] = repoErr
+				}
+				return nil
+			}
+		}
+		return wrapGraphQLError(err, ResourceTypeRepository)
+	}, g.retry)
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return labels, errs
+	}
+
+	var rateLimit rateLimitFragment
+	if json.Unmarshal(response["rateLimit"], &rateLimit) == nil {
+		g.budget.observeQuery(rateLimit)
+	}
+
+	for alias, i := range aliases {
+		if errs[i] != nil {
+			continue
+		}
+		var node *struct {
+			Labels labelPage
+		}
+		if err := json.Unmarshal(response[alias], &node); err != nil || node == nil {
+			errs[i] = &NotFoundError{ResourceType: ResourceTypeRepository}
+			continue
+		}
+
+		labels[i] = node.Labels.labels()
+		if node.Labels.PageInfo.HasNextPage {
+			cursor := graphql.String(node.Labels.PageInfo.EndCursor)
+			rest, err := g.listLabels(ctx, repos[i], &cursor)
+			if err != nil {
+				labels[i], errs[i] = nil, err
+				continue
+			}
+			labels[i] = append(labels[i], rest...)
+		}
+	}
+
+	return labels, errs
+}
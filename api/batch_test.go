@@ -0,0 +1,288 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package api
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+func TestGraphQLAPI_ApplyLabelChanges(t *testing.T) {
+	repo := option.Repo{Owner: "owner", Repo: "repo"}
+	changes := []option.LabelChange{
+		{Type: option.LabelChangeCreate, Label: option.Label{Name: "bug", Color: "d73a4a"}},
+		{Type: option.LabelChangeUpdate, Label: option.Label{Name: "old", NewName: "new", Color: "ffffff"}},
+		{Type: option.LabelChangeDelete, Label: option.Label{Name: "missing"}},
+		{Type: option.LabelChangeDelete, Label: option.Label{Name: "wontfix"}},
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr []string
+	}{
+		{
+			name: "errors of each change",
+			mock: func() {
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					BodyString(`label1: label\(name: \$label1\).*label2: label.*label3: label`).
+					Reply(200).
+					JSON(map[string]any{
+						"data": map[string]any{
+							"repository": map[string]any{
+								"id":     "R_123",
+								"label1": map[string]any{"id": "LA_1"},
+								"label2": nil,
+								"label3": map[string]any{"id": "LA_3"},
+							},
+						},
+					})
+				// The missing label is left out of the mutation
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					BodyString(`change0: createLabel\(input: \$input0\).*change1: updateLabel\(input: \$input1\).*change3: deleteLabel\(input: \$input3\)`).
+					Reply(200).
+					JSON(map[string]any{
+						"data": map[string]any{
+							"change0": nil,
+							"change1": map[string]any{"clientMutationId": nil},
+							"change3": nil,
+						},
+						"errors": []map[string]any{
+							{
+								"type":    "UNPROCESSABLE",
+								"path":    []any{"change0"},
+								"message": "Name already exists on this repository",
+							},
+							{
+								"type":    "FORBIDDEN",
+								"path":    []any{"change3"},
+								"message": "You don't have permission to delete this label",
+							},
+						},
+					})
+			},
+			wantErr: []string{"label already exists", "", "label not found", "forbidden"},
+		},
+		{
+			name: "whole request failed",
+			mock: func() {
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(map[string]any{
+						"data": map[string]any{
+							"repository": map[string]any{
+								"id":     "R_123",
+								"label1": map[string]any{"id": "LA_1"},
+								"label2": nil,
+								"label3": map[string]any{"id": "LA_3"},
+							},
+						},
+					})
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(map[string]any{
+						"errors": []map[string]any{
+							{
+								"type":    "FORBIDDEN",
+								"message": "You don't have permission to access this repository",
+							},
+						},
+					})
+			},
+			wantErr: []string{"forbidden", "forbidden", "label not found", "forbidden"},
+		},
+		{
+			name: "repository not found",
+			mock: func() {
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(map[string]any{
+						"data": map[string]any{
+							"repository": nil,
+						},
+						"errors": []map[string]any{
+							{
+								"type":    "NOT_FOUND",
+								"path":    []any{"repository"},
+								"message": "Could not resolve to a Repository with the name 'owner/repo'.",
+							},
+						},
+					})
+			},
+			wantErr: []string{"repository not found", "repository not found", "repository not found", "repository not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			tt.mock()
+
+			g := newTestGraphQLAPI(t)
+			errs := g.ApplyLabelChanges(context.Background(), repo, changes)
+
+			if len(errs) != len(changes) {
+				t.Fatalf("ApplyLabelChanges() returned %d errors, want %d", len(errs), len(changes))
+			}
+			for i, err := range errs {
+				got := ""
+				if err != nil {
+					got = err.Error()
+				}
+				if got != tt.wantErr[i] {
+					t.Errorf("ApplyLabelChanges() error of change %d = %q, want %q", i, got, tt.wantErr[i])
+				}
+			}
+
+			if !gock.IsDone() {
+				t.Errorf("pending mocks: %d", len(gock.Pending()))
+			}
+		})
+	}
+}
+
+func TestGraphQLAPI_ApplyLabelChanges_OnlyCreates(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		Reply(200).
+		JSON(map[string]any{
+			"data": map[string]any{
+				"repository": map[string]any{"id": "R_123"},
+			},
+		})
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"repositoryId":"R_123"`).
+		Reply(200).
+		JSON(map[string]any{
+			"data": map[string]any{
+				"change0": map[string]any{"clientMutationId": nil},
+				"change1": map[string]any{"clientMutationId": nil},
+			},
+		})
+
+	g := newTestGraphQLAPI(t)
+	errs := g.ApplyLabelChanges(context.Background(), option.Repo{Owner: "owner", Repo: "repo"}, []option.LabelChange{
+		{Type: option.LabelChangeCreate, Label: option.Label{Name: "bug", Color: "d73a4a"}},
+		{Type: option.LabelChangeCreate, Label: option.Label{Name: "enhancement", Color: "a2eeef"}},
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("ApplyLabelChanges() error of change %d = %v", i, err)
+		}
+	}
+	if !gock.IsDone() {
+		t.Errorf("pending mocks: %d", len(gock.Pending()))
+	}
+}
+
+func TestGraphQLAPI_ListLabelsOfRepos(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`repo0: repository\(owner: \$owner0, name: \$name0\).*repo2: repository`).
+		Reply(200).
+		JSON(map[string]any{
+			"data": map[string]any{
+				"repo0": map[string]any{
+					"labels": map[string]any{
+						"nodes": []map[string]any{
+							{"name": "bug", "color": "d73a4a", "description": "Something isn't working"},
+						},
+						"pageInfo": map[string]any{"hasNextPage": false, "endCursor": "c1"},
+					},
+				},
+				"repo1": nil,
+				"repo2": map[string]any{
+					"labels": map[string]any{
+						"nodes": []map[string]any{
+							{"name": "enhancement", "color": "a2eeef", "description": ""},
+						},
+						"pageInfo": map[string]any{"hasNextPage": true, "endCursor": "c2"},
+					},
+				},
+			},
+			"errors": []map[string]any{
+				{
+					"type":    "NOT_FOUND",
+					"path":    []any{"repo1"},
+					"message": "Could not resolve to a Repository with the name 'owner/missing'.",
+				},
+			},
+		})
+	// Only the repository with more labels is paginated
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"cursor":"c2"`).
+		Reply(200).
+		JSON(map[string]any{
+			"data": map[string]any{
+				"repository": map[string]any{
+					"labels": map[string]any{
+						"nodes": []map[string]any{
+							{"name": "question", "color": "d876e3", "description": ""},
+						},
+						"pageInfo": map[string]any{"hasNextPage": false, "endCursor": "c3"},
+					},
+				},
+			},
+		})
+
+	g := newTestGraphQLAPI(t)
+	labels, errs := g.ListLabelsOfRepos(context.Background(), []option.Repo{
+		{Owner: "owner", Repo: "repo"},
+		{Owner: "owner", Repo: "missing"},
+		{Owner: "owner", Repo: "many"},
+	})
+
+	wantLabels := [][]option.Label{
+		{{Name: "bug", Color: "d73a4a", Description: "Something isn't working"}},
+		nil,
+		{{Name: "enhancement", Color: "a2eeef"}, {Name: "question", Color: "d876e3"}},
+	}
+	if !reflect.DeepEqual(labels, wantLabels) {
+		t.Errorf("ListLabelsOfRepos() labels = %v, want %v", labels, wantLabels)
+	}
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("ListLabelsOfRepos() errors = %v, want errors only for owner/missing", errs)
+	}
+	if errs[1] == nil || errs[1].Error() != "repository not found" {
+		t.Errorf("ListLabelsOfRepos() error of owner/missing = %v, want repository not found", errs[1])
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("pending mocks: %d", len(gock.Pending()))
+	}
+}
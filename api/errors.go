@@ -46,6 +46,7 @@ type ResourceType string
 const (
 	ResourceTypeRepository ResourceType = "repository"
 	ResourceTypeLabel      ResourceType = "label"
+	ResourceTypeOwner      ResourceType = "owner"
 )
 
 // NotFoundError indicates 404 Not Found response
@@ -22,12 +22,15 @@ THE SOFTWARE.
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
 	graphql "github.com/cli/shurcooL-graphql"
@@ -41,25 +44,42 @@ type GraphQLAPI struct {
 	repoIDCache map[string]option.GraphQLID
 	repoIDMu    sync.RWMutex
 	retry       retryConfig
+	// budget tracks the rate limit of the host
+	budget *rateBudget
 }
 
-// NewGraphQLAPI creates a new GraphQL API client
-func NewGraphQLAPI() (*GraphQLAPI, error) {
-	client, err := api.NewGraphQLClient(api.ClientOptions{})
+// NewGraphQLAPI creates a new GraphQL API client for host, such as a GitHub
+// Enterprise Server hostname. An empty host uses the default host of gh.
+func NewGraphQLAPI(host string) (*GraphQLAPI, error) {
+	budget := newRateBudget()
+	client, err := api.NewGraphQLClient(api.ClientOptions{
+		Host:      host,
+		Transport: &rateLimitTransport{base: http.DefaultTransport, budget: budget},
+	})
 	if err != nil {
 		return nil, err
 	}
+	retry := defaultRetryConfig()
+	retry.rateLimitWait = budget.retryWait
 	return &GraphQLAPI{
 		client:      client,
 		repoIDCache: make(map[string]option.GraphQLID),
-		retry:       defaultRetryConfig(),
+		retry:       retry,
+		budget:      budget,
 	}, nil
 }
 
-// query runs a GraphQL query with automatic retry on rate limit and transient errors.
-func (g *GraphQLAPI) query(name string, q any, variables map[string]any, resourceType ResourceType) error {
-	return withRetry(func() error {
-		if err := g.client.Query(name, q, variables); err != nil {
+// RateLimitDelay returns how long to wait before starting more work, to stay
+// within the rate limit of the host
+func (g *GraphQLAPI) RateLimitDelay() time.Duration {
+	return g.budget.delay()
+}
+
+// query runs a GraphQL query with automatic retry on rate limit and transient
+// errors. Canceling ctx aborts the query.
+func (g *GraphQLAPI) query(ctx context.Context, name string, q any, variables map[string]any, resourceType ResourceType) error {
+	return withRetry(ctx, func() error {
+		if err := g.client.QueryWithContext(ctx, name, q, variables); err != nil {
 			return wrapGraphQLError(err, resourceType)
 		}
 		return nil
@@ -67,10 +87,11 @@ func (g *GraphQLAPI) query(name string, q any, variables map[string]any, resourc
 }
 
 // mutate runs an idempotent GraphQL mutation with automatic retry on rate
-// limit and transient errors.
-func (g *GraphQLAPI) mutate(name string, m any, variables map[string]any, resourceType ResourceType) error {
-	return withRetry(func() error {
-		if err := g.client.Mutate(name, m, variables); err != nil {
+// limit and transient errors. Canceling ctx stops the retries, but not a
+// request already sent, whose outcome would otherwise be unknown.
+func (g *GraphQLAPI) mutate(ctx context.Context, name string, m any, variables map[string]any, resourceType ResourceType) error {
+	return withRetry(ctx, func() error {
+		if err := g.client.MutateWithContext(context.WithoutCancel(ctx), name, m, variables); err != nil {
 			return wrapGraphQLError(err, resourceType)
 		}
 		return nil
@@ -80,12 +101,13 @@ func (g *GraphQLAPI) mutate(name string, m any, variables map[string]any, resour
 // mutateNonIdempotent runs a non-idempotent GraphQL mutation. Retrying on a
 // transient/network error could observe AlreadyExists/NotFound from a
 // previously-committed call and report a false failure, so this path retries
-// only on rate-limit errors (which are gateway-rejected before commit).
-func (g *GraphQLAPI) mutateNonIdempotent(name string, m any, variables map[string]any, resourceType ResourceType) error {
+// only on rate-limit errors (which are gateway-rejected before commit). Like
+// mutate, canceling ctx does not abort a request already sent.
+func (g *GraphQLAPI) mutateNonIdempotent(ctx context.Context, name string, m any, variables map[string]any, resourceType ResourceType) error {
 	cfg := g.retry
 	cfg.retryable = IsRateLimit
-	return withRetry(func() error {
-		if err := g.client.Mutate(name, m, variables); err != nil {
+	return withRetry(ctx, func() error {
+		if err := g.client.MutateWithContext(context.WithoutCancel(ctx), name, m, variables); err != nil {
 			return wrapGraphQLError(err, resourceType)
 		}
 		return nil
@@ -93,7 +115,7 @@ func (g *GraphQLAPI) mutateNonIdempotent(name string, m any, variables map[strin
 }
 
 // GetRepositoryID fetches the GraphQL node ID for a repository
-func (g *GraphQLAPI) GetRepositoryID(repo option.Repo) (option.GraphQLID, error) {
+func (g *GraphQLAPI) GetRepositoryID(ctx context.Context, repo option.Repo) (option.GraphQLID, error) {
 	cacheKey := repo.String()
 
 	// Check cache with read lock
@@ -115,7 +137,7 @@ func (g *GraphQLAPI) GetRepositoryID(repo option.Repo) (option.GraphQLID, error)
 		"name":  graphql.String(repo.Repo),
 	}
 
-	if err := g.query("RepositoryID", &query, variables, ResourceTypeRepository); err != nil {
+	if err := g.query(ctx, "RepositoryID", &query, variables, ResourceTypeRepository); err != nil {
 		return "", err
 	}
 
@@ -128,7 +150,7 @@ func (g *GraphQLAPI) GetRepositoryID(repo option.Repo) (option.GraphQLID, error)
 }
 
 // GetLabelID fetches the GraphQL node ID for a label in a repository
-func (g *GraphQLAPI) GetLabelID(repo option.Repo, labelName string) (option.GraphQLID, error) {
+func (g *GraphQLAPI) GetLabelID(ctx context.Context, repo option.Repo, labelName string) (option.GraphQLID, error) {
 	var query struct {
 		Repository struct {
 			Label struct {
@@ -143,7 +165,7 @@ func (g *GraphQLAPI) GetLabelID(repo option.Repo, labelName string) (option.Grap
 		"labelName": graphql.String(labelName),
 	}
 
-	if err := g.query("LabelID", &query, variables, ResourceTypeLabel); err != nil {
+	if err := g.query(ctx, "LabelID", &query, variables, ResourceTypeLabel); err != nil {
 		return "", err
 	}
 
@@ -154,26 +176,65 @@ func (g *GraphQLAPI) GetLabelID(repo option.Repo, labelName string) (option.Grap
 	return option.GraphQLID(query.Repository.Label.ID), nil
 }
 
+// GetLabelUsage counts the issues, pull requests and discussions a label is
+// attached to. Labels have no discussions connection, so discussions are
+// counted with a search.
+func (g *GraphQLAPI) GetLabelUsage(ctx context.Context, repo option.Repo, labelName string) (option.LabelUsage, error) {
+	var query struct {
+		Repository struct {
+			Label struct {
+				ID     string
+				Issues struct {
+					TotalCount int
+				}
+				PullRequests struct {
+					TotalCount int
+				}
+			} `graphql:"label(name: $labelName)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+		Search struct {
+			DiscussionCount int
+		} `graphql:"search(query: $query, type: DISCUSSION, first: 0)"`
+	}
+
+	variables := map[string]any{
+		"owner":     graphql.String(repo.Owner),
+		"name":      graphql.String(repo.Repo),
+		"labelName": graphql.String(labelName),
+		"query":     graphql.String(fmt.Sprintf("repo:%s/%s label:\"%s\"", repo.Owner, repo.Repo, escapeSearchQuery(labelName))),
+	}
+
+	if err := g.query(ctx, "LabelUsage", &query, variables, ResourceTypeLabel); err != nil {
+		return option.LabelUsage{}, err
+	}
+
+	if query.Repository.Label.ID == "" {
+		return option.LabelUsage{}, &NotFoundError{ResourceType: ResourceTypeLabel}
+	}
+
+	return option.LabelUsage{
+		Issues:       query.Repository.Label.Issues.TotalCount,
+		PullRequests: query.Repository.Label.PullRequests.TotalCount,
+		Discussions:  query.Search.DiscussionCount,
+	}, nil
+}
+
 // ListLabels fetches all labels in a repository with pagination
-func (g *GraphQLAPI) ListLabels(repo option.Repo) ([]option.Label, error) {
+func (g *GraphQLAPI) ListLabels(ctx context.Context, repo option.Repo) ([]option.Label, error) {
+	return g.listLabels(ctx, repo, nil)
+}
+
+// listLabels fetches the labels in a repository from the page after cursor
+// on, or from the first page if cursor is nil
+func (g *GraphQLAPI) listLabels(ctx context.Context, repo option.Repo, cursor *graphql.String) ([]option.Label, error) {
 	var allLabels []option.Label
-	var cursor *graphql.String
 
 	for {
 		var query struct {
 			Repository struct {
-				Labels struct {
-					Nodes []struct {
-						Name        string
-						Color       string
-						Description string
-					}
-					PageInfo struct {
-						HasNextPage bool
-						EndCursor   string
-					}
-				} `graphql:"labels(first: 100, after: $cursor)"`
+				Labels labelPage `graphql:"labels(first: 100, after: $cursor)"`
 			} `graphql:"repository(owner: $owner, name: $name)"`
+			RateLimit rateLimitFragment
 		}
 
 		variables := map[string]any{
@@ -182,17 +243,12 @@ func (g *GraphQLAPI) ListLabels(repo option.Repo) ([]option.Label, error) {
 			"cursor": cursor,
 		}
 
-		if err := g.query("RepositoryLabels", &query, variables, ResourceTypeRepository); err != nil {
+		if err := g.query(ctx, "RepositoryLabels", &query, variables, ResourceTypeRepository); err != nil {
 			return nil, err
 		}
+		g.budget.observeQuery(query.RateLimit)
 
-		for _, node := range query.Repository.Labels.Nodes {
-			allLabels = append(allLabels, option.Label{
-				Name:        node.Name,
-				Color:       node.Color,
-				Description: node.Description,
-			})
-		}
+		allLabels = append(allLabels, query.Repository.Labels.labels()...)
 
 		if !query.Repository.Labels.PageInfo.HasNextPage {
 			break
@@ -204,9 +260,186 @@ func (g *GraphQLAPI) ListLabels(repo option.Repo) ([]option.Label, error) {
 	return allLabels, nil
 }
 
+// labelPage is a page of the labels of a repository
+type labelPage struct {
+	Nodes []struct {
+		Name        string
+		Color       string
+		Description string
+	}
+	PageInfo struct {
+		HasNextPage bool
+		EndCursor   string
+	}
+}
+
+func (p labelPage) labels() []option.Label {
+	var labels []option.Label
+	for _, node := range p.Nodes {
+		labels = append(labels, option.Label{
+			Name:        node.Name,
+			Color:       node.Color,
+			Description: node.Description,
+		})
+	}
+	return labels
+}
+
+// ListRepositories fetches all repositories owned by an organization or user with pagination
+func (g *GraphQLAPI) ListRepositories(ctx context.Context, owner string) ([]option.Repository, error) {
+	var allRepos []option.Repository
+	var cursor *graphql.String
+
+	for {
+		var query struct {
+			RepositoryOwner *struct {
+				Repositories struct {
+					Nodes    []repositoryFragment
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				} `graphql:"repositories(first: 100, after: $cursor, ownerAffiliations: [OWNER], orderBy: {field: NAME, direction: ASC})"`
+			} `graphql:"repositoryOwner(login: $owner)"`
+			RateLimit rateLimitFragment
+		}
+
+		variables := map[string]any{
+			"owner":  graphql.String(owner),
+			"cursor": cursor,
+		}
+
+		if err := g.query(ctx, "OwnerRepositories", &query, variables, ResourceTypeOwner); err != nil {
+			return nil, err
+		}
+		g.budget.observeQuery(query.RateLimit)
+
+		if query.RepositoryOwner == nil {
+			return nil, &NotFoundError{ResourceType: ResourceTypeOwner}
+		}
+
+		for _, node := range query.RepositoryOwner.Repositories.Nodes {
+			allRepos = append(allRepos, node.toRepository())
+		}
+
+		if !query.RepositoryOwner.Repositories.PageInfo.HasNextPage {
+			break
+		}
+		endCursor := graphql.String(query.RepositoryOwner.Repositories.PageInfo.EndCursor)
+		cursor = &endCursor
+	}
+
+	return allRepos, nil
+}
+
+// SearchRepositories fetches the repositories matching a GitHub search query
+// (e.g., "org:acme topic:backend") with pagination. GitHub returns at most
+// 1,000 results for a search.
+func (g *GraphQLAPI) SearchRepositories(ctx context.Context, searchQuery string) ([]option.Repository, error) {
+	var allRepos []option.Repository
+	var cursor *graphql.String
+
+	for {
+		var query struct {
+			Search struct {
+				Nodes []struct {
+					Repository repositoryFragment `graphql:"... on Repository"`
+				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			} `graphql:"search(query: $query, type: REPOSITORY, first: 100, after: $cursor)"`
+			RateLimit rateLimitFragment
+		}
+
+		variables := map[string]any{
+			"query":  graphql.String(searchQuery),
+			"cursor": cursor,
+		}
+
+		if err := g.query(ctx, "SearchRepositories", &query, variables, ResourceTypeRepository); err != nil {
+			return nil, err
+		}
+		g.budget.observeQuery(query.RateLimit)
+
+		for _, node := range query.Search.Nodes {
+			allRepos = append(allRepos, node.Repository.toRepository())
+		}
+
+		if !query.Search.PageInfo.HasNextPage {
+			break
+		}
+		endCursor := graphql.String(query.Search.PageInfo.EndCursor)
+		cursor = &endCursor
+	}
+
+	return allRepos, nil
+}
+
+type repositoryFragment struct {
+	Name  string
+	Owner struct {
+		Login string
+	}
+	IsArchived       bool
+	IsFork           bool
+	IsTemplate       bool
+	HasIssuesEnabled bool
+	Visibility       string
+	PrimaryLanguage  *struct {
+		Name string
+	}
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string
+			}
+		}
+	} `graphql:"repositoryTopics(first: 100)"`
+}
+
+func (f repositoryFragment) toRepository() option.Repository {
+	repo := option.Repository{
+		Repo:             option.Repo{Owner: f.Owner.Login, Repo: f.Name},
+		IsArchived:       f.IsArchived,
+		IsFork:           f.IsFork,
+		IsTemplate:       f.IsTemplate,
+		HasIssuesEnabled: f.HasIssuesEnabled,
+		Visibility:       strings.ToLower(f.Visibility),
+	}
+	if f.PrimaryLanguage != nil {
+		repo.Language = f.PrimaryLanguage.Name
+	}
+	for _, node := range f.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, node.Topic.Name)
+	}
+	return repo
+}
+
+// Inputs of the label mutations. shurcooL-graphql derives the GraphQL types of
+// variables from the Go type names, so these are named after the input types.
+type (
+	CreateLabelInput struct {
+		RepositoryID string `json:"repositoryId"`
+		Name         string `json:"name"`
+		Color        string `json:"color"`
+		Description  string `json:"description,omitempty"`
+	}
+	UpdateLabelInput struct {
+		ID          string `json:"id"`
+		Name        string `json:"name,omitempty"`
+		Color       string `json:"color,omitempty"`
+		Description string `json:"description,omitempty"`
+	}
+	DeleteLabelInput struct {
+		ID string `json:"id"`
+	}
+)
+
 // CreateLabel creates a new label in a repository
-func (g *GraphQLAPI) CreateLabel(label option.Label, repo option.Repo) error {
-	repoID, err := g.GetRepositoryID(repo)
+func (g *GraphQLAPI) CreateLabel(ctx context.Context, label option.Label, repo option.Repo) error {
+	repoID, err := g.GetRepositoryID(ctx, repo)
 	if err != nil {
 		return err
 	}
@@ -219,13 +452,6 @@ func (g *GraphQLAPI) CreateLabel(label option.Label, repo option.Repo) error {
 		} `graphql:"createLabel(input: $input)"`
 	}
 
-	type CreateLabelInput struct {
-		RepositoryID string `json:"repositoryId"`
-		Name         string `json:"name"`
-		Color        string `json:"color"`
-		Description  string `json:"description,omitempty"`
-	}
-
 	variables := map[string]any{
 		"input": CreateLabelInput{
 			RepositoryID: string(repoID),
@@ -235,12 +461,13 @@ func (g *GraphQLAPI) CreateLabel(label option.Label, repo option.Repo) error {
 		},
 	}
 
-	return g.mutateNonIdempotent("CreateLabel", &mutation, variables, ResourceTypeLabel)
+	return g.mutateNonIdempotent(ctx, "CreateLabel", &mutation, variables, ResourceTypeLabel)
 }
 
-// UpdateLabel updates an existing label in a repository
-func (g *GraphQLAPI) UpdateLabel(label option.Label, repo option.Repo) error {
-	labelID, err := g.GetLabelID(repo, label.Name)
+// UpdateLabel updates an existing label in a repository.
+// The label is looked up by Name and renamed to NewName if it is set.
+func (g *GraphQLAPI) UpdateLabel(ctx context.Context, label option.Label, repo option.Repo) error {
+	labelID, err := g.GetLabelID(ctx, repo, label.Name)
 	if err != nil {
 		return err
 	}
@@ -253,28 +480,31 @@ func (g *GraphQLAPI) UpdateLabel(label option.Label, repo option.Repo) error {
 		} `graphql:"updateLabel(input: $input)"`
 	}
 
-	type UpdateLabelInput struct {
-		ID          string `json:"id"`
-		Name        string `json:"name,omitempty"`
-		Color       string `json:"color,omitempty"`
-		Description string `json:"description,omitempty"`
-	}
-
 	variables := map[string]any{
-		"input": UpdateLabelInput{
-			ID:          string(labelID),
-			Name:        label.Name,
-			Color:       label.Color,
-			Description: label.Description,
-		},
+		"input": updateLabelInput(labelID, label),
 	}
 
-	return g.mutate("UpdateLabel", &mutation, variables, ResourceTypeLabel)
+	return g.mutate(ctx, "UpdateLabel", &mutation, variables, ResourceTypeLabel)
+}
+
+// updateLabelInput returns the input updating the label with the given ID to
+// label, renaming it to label.NewName if set
+func updateLabelInput(labelID option.GraphQLID, label option.Label) UpdateLabelInput {
+	name := label.Name
+	if label.NewName != "" {
+		name = label.NewName
+	}
+	return UpdateLabelInput{
+		ID:          string(labelID),
+		Name:        name,
+		Color:       label.Color,
+		Description: label.Description,
+	}
 }
 
 // DeleteLabel deletes a label from a repository
-func (g *GraphQLAPI) DeleteLabel(label string, repo option.Repo) error {
-	labelID, err := g.GetLabelID(repo, label)
+func (g *GraphQLAPI) DeleteLabel(ctx context.Context, label string, repo option.Repo) error {
+	labelID, err := g.GetLabelID(ctx, repo, label)
 	if err != nil {
 		return err
 	}
@@ -285,17 +515,13 @@ func (g *GraphQLAPI) DeleteLabel(label string, repo option.Repo) error {
 		} `graphql:"deleteLabel(input: $input)"`
 	}
 
-	type DeleteLabelInput struct {
-		ID string `json:"id"`
-	}
-
 	variables := map[string]any{
 		"input": DeleteLabelInput{
 			ID: string(labelID),
 		},
 	}
 
-	return g.mutateNonIdempotent("DeleteLabel", &mutation, variables, ResourceTypeLabel)
+	return g.mutateNonIdempotent(ctx, "DeleteLabel", &mutation, variables, ResourceTypeLabel)
 }
 
 // wrapGraphQLError converts GraphQL API errors to custom error types
@@ -304,6 +530,11 @@ func wrapGraphQLError(err error, resourceType ResourceType) error {
 		return nil
 	}
 
+	// A canceled request is not an error of the API, and is not retried
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
 	// Check for HTTP-level errors first (5xx, 429) so they map to typed errors
 	// regardless of response body.
 	var httpErr *api.HTTPError
@@ -404,16 +635,16 @@ func escapeSearchQuery(s string) string {
 }
 
 // SearchLabelables searches for issues, pull requests, and discussions with a specific label
-func (g *GraphQLAPI) SearchLabelables(repo option.Repo, labelName string) ([]option.Labelable, error) {
+func (g *GraphQLAPI) SearchLabelables(ctx context.Context, repo option.Repo, labelName string) ([]option.Labelable, error) {
 	var allLabelables []option.Labelable
 
-	issuesAndPRs, err := g.searchIssuesAndPRs(repo, labelName)
+	issuesAndPRs, err := g.searchIssuesAndPRs(ctx, repo, labelName)
 	if err != nil {
 		return nil, err
 	}
 	allLabelables = append(allLabelables, issuesAndPRs...)
 
-	discussions, err := g.searchDiscussions(repo, labelName)
+	discussions, err := g.searchDiscussions(ctx, repo, labelName)
 	if err != nil {
 		return nil, err
 	}
@@ -423,7 +654,7 @@ func (g *GraphQLAPI) SearchLabelables(repo option.Repo, labelName string) ([]opt
 }
 
 // searchIssuesAndPRs searches for issues and pull requests with a specific label
-func (g *GraphQLAPI) searchIssuesAndPRs(repo option.Repo, labelName string) ([]option.Labelable, error) {
+func (g *GraphQLAPI) searchIssuesAndPRs(ctx context.Context, repo option.Repo, labelName string) ([]option.Labelable, error) {
 	var allLabelables []option.Labelable
 	var cursor *graphql.String
 
@@ -449,7 +680,7 @@ func (g *GraphQLAPI) searchIssuesAndPRs(repo option.Repo, labelName string) ([]o
 			"cursor": cursor,
 		}
 
-		if err := g.query("SearchIssuesAndPRs", &query, variables, ResourceTypeRepository); err != nil {
+		if err := g.query(ctx, "SearchIssuesAndPRs", &query, variables, ResourceTypeRepository); err != nil {
 			return nil, err
 		}
 
@@ -495,7 +726,7 @@ type pullRequestFragment struct {
 }
 
 // searchDiscussions searches for discussions with a specific label in a repository
-func (g *GraphQLAPI) searchDiscussions(repo option.Repo, labelName string) ([]option.Labelable, error) {
+func (g *GraphQLAPI) searchDiscussions(ctx context.Context, repo option.Repo, labelName string) ([]option.Labelable, error) {
 	var allLabelables []option.Labelable
 	var cursor *graphql.String
 
@@ -519,7 +750,7 @@ func (g *GraphQLAPI) searchDiscussions(repo option.Repo, labelName string) ([]op
 			"cursor": cursor,
 		}
 
-		if err := g.query("SearchDiscussions", &query, variables, ResourceTypeRepository); err != nil {
+		if err := g.query(ctx, "SearchDiscussions", &query, variables, ResourceTypeRepository); err != nil {
 			// Treat "discussions disabled" as an empty result rather than an error.
 			errMsg := err.Error()
 			if strings.Contains(errMsg, "does not have discussions enabled") ||
@@ -557,7 +788,7 @@ type discussionFragment struct {
 }
 
 // AddLabelsToLabelable adds labels to a labelable resource (issue, PR, or discussion)
-func (g *GraphQLAPI) AddLabelsToLabelable(labelableID option.GraphQLID, labelIDs []option.GraphQLID) error {
+func (g *GraphQLAPI) AddLabelsToLabelable(ctx context.Context, labelableID option.GraphQLID, labelIDs []option.GraphQLID) error {
 	var mutation struct {
 		AddLabelsToLabelable struct {
 			ClientMutationID *string
@@ -582,11 +813,11 @@ func (g *GraphQLAPI) AddLabelsToLabelable(labelableID option.GraphQLID, labelIDs
 		},
 	}
 
-	return g.mutate("AddLabelsToLabelable", &mutation, variables, ResourceTypeLabel)
+	return g.mutate(ctx, "AddLabelsToLabelable", &mutation, variables, ResourceTypeLabel)
 }
 
 // RemoveLabelsFromLabelable removes labels from a labelable resource (issue, PR, or discussion)
-func (g *GraphQLAPI) RemoveLabelsFromLabelable(labelableID option.GraphQLID, labelIDs []option.GraphQLID) error {
+func (g *GraphQLAPI) RemoveLabelsFromLabelable(ctx context.Context, labelableID option.GraphQLID, labelIDs []option.GraphQLID) error {
 	var mutation struct {
 		RemoveLabelsFromLabelable struct {
 			ClientMutationID *string
@@ -611,5 +842,5 @@ func (g *GraphQLAPI) RemoveLabelsFromLabelable(labelableID option.GraphQLID, lab
 		},
 	}
 
-	return g.mutate("RemoveLabelsFromLabelable", &mutation, variables, ResourceTypeLabel)
+	return g.mutate(ctx, "RemoveLabelsFromLabelable", &mutation, variables, ResourceTypeLabel)
 }
@@ -27,6 +27,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"reflect"
 	"testing"
 	"time"
 
@@ -51,9 +52,10 @@ func newTestGraphQLAPI(t *testing.T) *GraphQLAPI {
 			maxAttempts: 3,
 			baseDelay:   0,
 			maxDelay:    0,
-			sleep:       func(time.Duration) {},
+			sleep:       func(context.Context, time.Duration) error { return nil },
 			retryable:   isRetryable,
 		},
+		budget: newRateBudget(),
 	}
 }
 
@@ -117,7 +119,7 @@ func TestGraphQLAPI_GetRepositoryID(t *testing.T) {
 			}
 
 			g := newTestGraphQLAPI(t)
-			got, err := g.GetRepositoryID(tt.repo)
+			got, err := g.GetRepositoryID(context.Background(), tt.repo)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetRepositoryID() error = %v, wantErr %v", err, tt.wantErr)
@@ -164,7 +166,7 @@ func TestGraphQLAPI_GetRepositoryID_Cache(t *testing.T) {
 	repo := option.Repo{Owner: "owner", Repo: "repo"}
 
 	// First call - should make HTTP request
-	got1, err := g.GetRepositoryID(repo)
+	got1, err := g.GetRepositoryID(context.Background(), repo)
 	if err != nil {
 		t.Fatalf("first GetRepositoryID() error = %v", err)
 	}
@@ -176,7 +178,7 @@ func TestGraphQLAPI_GetRepositoryID_Cache(t *testing.T) {
 	}
 
 	// Second call - should use cache, no HTTP request
-	got2, err := g.GetRepositoryID(repo)
+	got2, err := g.GetRepositoryID(context.Background(), repo)
 	if err != nil {
 		t.Fatalf("second GetRepositoryID() error = %v", err)
 	}
@@ -273,7 +275,7 @@ func TestGraphQLAPI_GetLabelID(t *testing.T) {
 			}
 
 			g := newTestGraphQLAPI(t)
-			got, err := g.GetLabelID(tt.repo, tt.labelName)
+			got, err := g.GetLabelID(context.Background(), tt.repo, tt.labelName)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetLabelID() error = %v, wantErr %v", err, tt.wantErr)
@@ -294,6 +296,96 @@ func TestGraphQLAPI_GetLabelID(t *testing.T) {
 	}
 }
 
+func TestGraphQLAPI_GetLabelUsage(t *testing.T) {
+	tests := []struct {
+		name       string
+		repo       option.Repo
+		labelName  string
+		mock       func()
+		want       option.LabelUsage
+		wantErr    bool
+		wantErrMsg string
+	}{
+		{
+			name:      "success",
+			repo:      option.Repo{Owner: "owner", Repo: "repo"},
+			labelName: "bug",
+			mock: func() {
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					BodyString(`repo:owner/repo label:\\"bug\\"`).
+					Reply(200).
+					JSON(map[string]any{
+						"data": map[string]any{
+							"repository": map[string]any{
+								"label": map[string]any{
+									"id":           "LA_123456",
+									"issues":       map[string]any{"totalCount": 3},
+									"pullRequests": map[string]any{"totalCount": 1},
+								},
+							},
+							"search": map[string]any{
+								"discussionCount": 2,
+							},
+						},
+					})
+			},
+			want:    option.LabelUsage{Issues: 3, PullRequests: 1, Discussions: 2},
+			wantErr: false,
+		},
+		{
+			name:      "label not found",
+			repo:      option.Repo{Owner: "owner", Repo: "repo"},
+			labelName: "nonexistent",
+			mock: func() {
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(map[string]any{
+						"data": map[string]any{
+							"repository": map[string]any{
+								"label": nil,
+							},
+							"search": map[string]any{
+								"discussionCount": 0,
+							},
+						},
+					})
+			},
+			wantErr:    true,
+			wantErrMsg: "label not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			if tt.mock != nil {
+				tt.mock()
+			}
+
+			g := newTestGraphQLAPI(t)
+			got, err := g.GetLabelUsage(context.Background(), tt.repo, tt.labelName)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetLabelUsage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.Error() != tt.wantErrMsg {
+				t.Errorf("GetLabelUsage() error = %v, wantErrMsg %v", err.Error(), tt.wantErrMsg)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetLabelUsage() = %v, want %v", got, tt.want)
+			}
+
+			if !gock.IsDone() {
+				t.Errorf("pending mocks: %d", len(gock.Pending()))
+			}
+		})
+	}
+}
+
 func TestGraphQLAPI_ListLabels(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -390,7 +482,7 @@ func TestGraphQLAPI_ListLabels(t *testing.T) {
 			}
 
 			g := newTestGraphQLAPI(t)
-			got, err := g.ListLabels(tt.repo)
+			got, err := g.ListLabels(context.Background(), tt.repo)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ListLabels() error = %v, wantErr %v", err, tt.wantErr)
@@ -405,7 +497,7 @@ func TestGraphQLAPI_ListLabels(t *testing.T) {
 				return
 			}
 			for i, label := range got {
-				if label != tt.want[i] {
+				if !reflect.DeepEqual(label, tt.want[i]) {
 					t.Errorf("ListLabels()[%d] = %v, want %v", i, label, tt.want[i])
 				}
 			}
@@ -461,7 +553,7 @@ func TestGraphQLAPI_ListLabels_Pagination(t *testing.T) {
 		})
 
 	g := newTestGraphQLAPI(t)
-	got, err := g.ListLabels(option.Repo{Owner: "owner", Repo: "repo"})
+	got, err := g.ListLabels(context.Background(), option.Repo{Owner: "owner", Repo: "repo"})
 	if err != nil {
 		t.Fatalf("ListLabels() error = %v", err)
 	}
@@ -476,7 +568,7 @@ func TestGraphQLAPI_ListLabels_Pagination(t *testing.T) {
 		return
 	}
 	for i, label := range got {
-		if label != want[i] {
+		if !reflect.DeepEqual(label, want[i]) {
 			t.Errorf("ListLabels()[%d] = %v, want %v", i, label, want[i])
 		}
 	}
@@ -486,6 +578,128 @@ func TestGraphQLAPI_ListLabels_Pagination(t *testing.T) {
 	}
 }
 
+func TestGraphQLAPI_ListRepositories(t *testing.T) {
+	defer gock.Off()
+
+	// First page
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`repositoryOwner\(login: \$owner\)`).
+		Reply(200).
+		JSON(map[string]any{
+			"data": map[string]any{
+				"repositoryOwner": map[string]any{
+					"repositories": map[string]any{
+						"nodes": []map[string]any{
+							{"name": "repo1", "owner": map[string]any{"login": "owner"}, "isArchived": false, "isFork": false, "isTemplate": false, "hasIssuesEnabled": true, "visibility": "PUBLIC", "primaryLanguage": map[string]any{"name": "Go"}, "repositoryTopics": map[string]any{"nodes": []map[string]any{{"topic": map[string]any{"name": "backend"}}}}},
+						},
+						"pageInfo": map[string]any{
+							"hasNextPage": true,
+							"endCursor":   "cursor1",
+						},
+					},
+				},
+			},
+		})
+
+	// Second page
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"cursor":"cursor1"`).
+		Reply(200).
+		JSON(map[string]any{
+			"data": map[string]any{
+				"repositoryOwner": map[string]any{
+					"repositories": map[string]any{
+						"nodes": []map[string]any{
+							{"name": "repo2", "owner": map[string]any{"login": "owner"}, "isArchived": true, "isFork": true, "isTemplate": true, "hasIssuesEnabled": false, "visibility": "INTERNAL", "primaryLanguage": nil, "repositoryTopics": map[string]any{"nodes": []map[string]any{}}},
+						},
+						"pageInfo": map[string]any{
+							"hasNextPage": false,
+							"endCursor":   "",
+						},
+					},
+				},
+			},
+		})
+
+	g := newTestGraphQLAPI(t)
+	got, err := g.ListRepositories(context.Background(), "owner")
+	if err != nil {
+		t.Fatalf("ListRepositories() error = %v", err)
+	}
+
+	want := []option.Repository{
+		{Repo: option.Repo{Owner: "owner", Repo: "repo1"}, HasIssuesEnabled: true, Visibility: "public", Language: "Go", Topics: []string{"backend"}},
+		{Repo: option.Repo{Owner: "owner", Repo: "repo2"}, IsArchived: true, IsFork: true, IsTemplate: true, Visibility: "internal"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListRepositories() = %v, want %v", got, want)
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("pending mocks: %d", len(gock.Pending()))
+	}
+}
+
+func TestGraphQLAPI_SearchRepositories(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"query":"org:acme topic:backend"`).
+		Reply(200).
+		JSON(map[string]any{
+			"data": map[string]any{
+				"search": map[string]any{
+					"nodes": []map[string]any{
+						{"name": "api", "owner": map[string]any{"login": "acme"}, "hasIssuesEnabled": true, "visibility": "PRIVATE", "repositoryTopics": map[string]any{"nodes": []map[string]any{{"topic": map[string]any{"name": "backend"}}}}},
+					},
+					"pageInfo": map[string]any{
+						"hasNextPage": false,
+						"endCursor":   "",
+					},
+				},
+			},
+		})
+
+	g := newTestGraphQLAPI(t)
+	got, err := g.SearchRepositories(context.Background(), "org:acme topic:backend")
+	if err != nil {
+		t.Fatalf("SearchRepositories() error = %v", err)
+	}
+
+	want := []option.Repository{
+		{Repo: option.Repo{Owner: "acme", Repo: "api"}, HasIssuesEnabled: true, Visibility: "private", Topics: []string{"backend"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchRepositories() = %v, want %v", got, want)
+	}
+
+	if !gock.IsDone() {
+		t.Errorf("pending mocks: %d", len(gock.Pending()))
+	}
+}
+
+func TestGraphQLAPI_ListRepositories_OwnerNotFound(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		Reply(200).
+		JSON(map[string]any{
+			"data": map[string]any{
+				"repositoryOwner": nil,
+			},
+		})
+
+	g := newTestGraphQLAPI(t)
+	_, err := g.ListRepositories(context.Background(), "missing")
+	if err == nil || err.Error() != "owner not found" {
+		t.Errorf("ListRepositories() error = %v, want owner not found", err)
+	}
+}
+
 func TestGraphQLAPI_CreateLabel(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -595,7 +809,7 @@ func TestGraphQLAPI_CreateLabel(t *testing.T) {
 			}
 
 			g := newTestGraphQLAPI(t)
-			err := g.CreateLabel(tt.label, tt.repo)
+			err := g.CreateLabel(context.Background(), tt.label, tt.repo)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateLabel() error = %v, wantErr %v", err, tt.wantErr)
@@ -656,6 +870,42 @@ func TestGraphQLAPI_UpdateLabel(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:  "rename",
+			label: option.Label{Name: "bug", Color: "ff0000", Description: "Updated description", NewName: "Bug"},
+			repo:  option.Repo{Owner: "owner", Repo: "repo"},
+			mock: func() {
+				// GetLabelID call looks up the current name
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					BodyString(`"labelName":"bug"`).
+					Reply(200).
+					JSON(map[string]any{
+						"data": map[string]any{
+							"repository": map[string]any{
+								"label": map[string]any{
+									"id": "LA_123456",
+								},
+							},
+						},
+					})
+				// UpdateLabel mutation sends the new name
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					BodyString(`"name":"Bug"`).
+					Reply(200).
+					JSON(map[string]any{
+						"data": map[string]any{
+							"updateLabel": map[string]any{
+								"label": map[string]any{
+									"id": "LA_123456",
+								},
+							},
+						},
+					})
+			},
+			wantErr: false,
+		},
 		{
 			name:  "label not found",
 			label: option.Label{Name: "nonexistent", Color: "ff0000", Description: "Description"},
@@ -708,7 +958,7 @@ func TestGraphQLAPI_UpdateLabel(t *testing.T) {
 			}
 
 			g := newTestGraphQLAPI(t)
-			err := g.UpdateLabel(tt.label, tt.repo)
+			err := g.UpdateLabel(context.Background(), tt.label, tt.repo)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UpdateLabel() error = %v, wantErr %v", err, tt.wantErr)
@@ -856,7 +1106,7 @@ func TestGraphQLAPI_DeleteLabel(t *testing.T) {
 			}
 
 			g := newTestGraphQLAPI(t)
-			err := g.DeleteLabel(tt.label, tt.repo)
+			err := g.DeleteLabel(context.Background(), tt.label, tt.repo)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeleteLabel() error = %v, wantErr %v", err, tt.wantErr)
@@ -1166,7 +1416,7 @@ func TestGraphQLAPI_SearchLabelables(t *testing.T) {
 			}
 
 			g := newTestGraphQLAPI(t)
-			got, err := g.SearchLabelables(tt.repo, tt.labelName)
+			got, err := g.SearchLabelables(context.Background(), tt.repo, tt.labelName)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SearchLabelables() error = %v, wantErr %v", err, tt.wantErr)
@@ -1296,7 +1546,7 @@ func TestGraphQLAPI_AddLabelsToLabelable(t *testing.T) {
 			}
 
 			g := newTestGraphQLAPI(t)
-			err := g.AddLabelsToLabelable(tt.labelableID, tt.labelIDs)
+			err := g.AddLabelsToLabelable(context.Background(), tt.labelableID, tt.labelIDs)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AddLabelsToLabelable() error = %v, wantErr %v", err, tt.wantErr)
@@ -1415,7 +1665,7 @@ func TestGraphQLAPI_RemoveLabelsFromLabelable(t *testing.T) {
 			}
 
 			g := newTestGraphQLAPI(t)
-			err := g.RemoveLabelsFromLabelable(tt.labelableID, tt.labelIDs)
+			err := g.RemoveLabelsFromLabelable(context.Background(), tt.labelableID, tt.labelIDs)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("RemoveLabelsFromLabelable() error = %v, wantErr %v", err, tt.wantErr)
@@ -1512,6 +1762,18 @@ func TestWrapGraphQLError_NetworkError(t *testing.T) {
 	}
 }
 
+func TestWrapGraphQLError_Canceled(t *testing.T) {
+	err := &url.Error{
+		Op:  "Post",
+		URL: "https://api.github.com/graphql",
+		Err: context.Canceled,
+	}
+	got := wrapGraphQLError(err, ResourceTypeRepository)
+	if IsTransient(got) || !errors.Is(got, context.Canceled) {
+		t.Errorf("wrapGraphQLError(%v) = %v, want the canceled error as is", err, got)
+	}
+}
+
 func TestGraphQLAPI_RetryOnTransient(t *testing.T) {
 	defer gock.Off()
 
@@ -1536,7 +1798,7 @@ func TestGraphQLAPI_RetryOnTransient(t *testing.T) {
 		})
 
 	g := newTestGraphQLAPI(t)
-	got, err := g.GetRepositoryID(option.Repo{Owner: "owner", Repo: "repo"})
+	got, err := g.GetRepositoryID(context.Background(), option.Repo{Owner: "owner", Repo: "repo"})
 	if err != nil {
 		t.Fatalf("GetRepositoryID() error = %v, want nil after retries", err)
 	}
@@ -1572,7 +1834,7 @@ func TestGraphQLAPI_RetryOnNetworkError(t *testing.T) {
 		})
 
 	g := newTestGraphQLAPI(t)
-	got, err := g.GetRepositoryID(option.Repo{Owner: "owner", Repo: "repo"})
+	got, err := g.GetRepositoryID(context.Background(), option.Repo{Owner: "owner", Repo: "repo"})
 	if err != nil {
 		t.Fatalf("GetRepositoryID() error = %v, want nil after retries", err)
 	}
@@ -1595,7 +1857,7 @@ func TestGraphQLAPI_RetryGivesUpAfterMaxAttempts(t *testing.T) {
 	}
 
 	g := newTestGraphQLAPI(t)
-	_, err := g.GetRepositoryID(option.Repo{Owner: "owner", Repo: "repo"})
+	_, err := g.GetRepositoryID(context.Background(), option.Repo{Owner: "owner", Repo: "repo"})
 	if err == nil {
 		t.Fatal("GetRepositoryID() error = nil, want transient error")
 	}
@@ -1626,7 +1888,7 @@ func TestGraphQLAPI_CreateLabel_NoRetryOnTransient(t *testing.T) {
 		BodyString("service unavailable")
 
 	g := newTestGraphQLAPI(t)
-	err := g.CreateLabel(option.Label{Name: "bug", Color: "d73a4a"}, option.Repo{Owner: "owner", Repo: "repo"})
+	err := g.CreateLabel(context.Background(), option.Label{Name: "bug", Color: "d73a4a"}, option.Repo{Owner: "owner", Repo: "repo"})
 	if err == nil {
 		t.Fatal("CreateLabel() error = nil, want transient error")
 	}
@@ -1667,7 +1929,7 @@ func TestGraphQLAPI_CreateLabel_RetriesOnRateLimit(t *testing.T) {
 		})
 
 	g := newTestGraphQLAPI(t)
-	err := g.CreateLabel(option.Label{Name: "bug", Color: "d73a4a"}, option.Repo{Owner: "owner", Repo: "repo"})
+	err := g.CreateLabel(context.Background(), option.Label{Name: "bug", Color: "d73a4a"}, option.Repo{Owner: "owner", Repo: "repo"})
 	if err != nil {
 		t.Fatalf("CreateLabel() error = %v, want nil after rate-limit retry", err)
 	}
@@ -1697,7 +1959,7 @@ func TestGraphQLAPI_DeleteLabel_NoRetryOnTransient(t *testing.T) {
 		BodyString("service unavailable")
 
 	g := newTestGraphQLAPI(t)
-	err := g.DeleteLabel("bug", option.Repo{Owner: "owner", Repo: "repo"})
+	err := g.DeleteLabel(context.Background(), "bug", option.Repo{Owner: "owner", Repo: "repo"})
 	if err == nil {
 		t.Fatal("DeleteLabel() error = nil, want transient error")
 	}
@@ -1729,7 +1991,7 @@ func TestGraphQLAPI_NoRetryOnNonRetryable(t *testing.T) {
 		})
 
 	g := newTestGraphQLAPI(t)
-	_, err := g.GetRepositoryID(option.Repo{Owner: "owner", Repo: "nonexistent"})
+	_, err := g.GetRepositoryID(context.Background(), option.Repo{Owner: "owner", Repo: "nonexistent"})
 	if err == nil {
 		t.Fatal("GetRepositoryID() error = nil, want NotFoundError")
 	}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// reservedQueries is how many queries of the highest cost seen the budget
+	// keeps points for, for the requests of jobs already running
+	reservedQueries = 10
+	// slowdownFraction starts slowing down when less than 1/slowdownFraction
+	// of the limit remains
+	slowdownFraction = 10
+)
+
+// rateBudget tracks the GraphQL rate limit of a host, shared by the requests
+// of every job. It is fed by the rateLimit field of queries and by the
+// x-ratelimit headers of every response.
+type rateBudget struct {
+	mu        sync.Mutex
+	limit     int
+	remaining int
+	resetAt   time.Time
+	// maxCost is the highest cost of a query seen
+	maxCost int
+	// retryAt is when requests may be made again after a rate limit response
+	retryAt time.Time
+	now     func() time.Time
+}
+
+func newRateBudget() *rateBudget {
+	return &rateBudget{now: time.Now}
+}
+
+// rateLimitFragment is the rateLimit field requested with queries
+type rateLimitFragment struct {
+	Limit     int
+	Cost      int
+	Remaining int
+	ResetAt   string
+}
+
+// rateLimitSelection is rateLimitFragment for queries written by hand
+const rateLimitSelection = "rateLimit { limit cost remaining resetAt }"
+
+// observeQuery records the rate limit returned with a query
+func (b *rateBudget) observeQuery(f rateLimitFragment) {
+	resetAt, err := time.Parse(time.RFC3339, f.ResetAt)
+	if err != nil {
+		return
+	}
+	b.observe(f.Limit, f.Remaining, resetAt)
+
+	b.mu.Lock()
+	b.maxCost = max(b.maxCost, f.Cost)
+	b.mu.Unlock()
+}
+
+// observe records the remaining points of the rate limit window ending at
+// resetAt. Responses of concurrent requests arrive in any order, so within a
+// window the lowest remaining points win.
+func (b *rateBudget) observe(limit, remaining int, resetAt time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case resetAt.After(b.resetAt):
+		b.limit, b.remaining, b.resetAt = limit, remaining, resetAt
+	case resetAt.Equal(b.resetAt):
+		b.remaining = min(b.remaining, remaining)
+	}
+	if remaining == 0 && resetAt.After(b.retryAt) {
+		b.retryAt = resetAt
+	}
+}
+
+// observeResponse records the x-ratelimit headers of a response, and the time
+// to wait given by a secondary rate limit response
+func (b *rateBudget) observeResponse(resp *http.Response) {
+	limit, errLimit := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	reset, errReset := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if errLimit == nil && errRemaining == nil && errReset == nil {
+		b.observe(limit, remaining, time.Unix(reset, 0))
+	}
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		b.mu.Lock()
+		if retryAt := b.now().Add(time.Duration(seconds) * time.Second); retryAt.After(b.retryAt) {
+			b.retryAt = retryAt
+		}
+		b.mu.Unlock()
+	}
+}
+
+// retryWait returns how long to wait before retrying a rate limited request,
+// 0 if the server did not tell
+func (b *rateBudget) retryWait() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return max(b.retryAt.Sub(b.now()), 0)
+}
+
+// delay returns how long to wait before starting more work. It waits for the
+// end of a rate limit, pauses until the window resets when only the points
+// reserved for requests in flight remain, and spreads the remaining points
+// over the rest of the window when they run low.
+func (b *rateBudget) delay() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if now.Before(b.retryAt) {
+		return b.retryAt.Sub(now)
+	}
+	if b.limit == 0 || !now.Before(b.resetAt) {
+		return 0
+	}
+
+	untilReset := b.resetAt.Sub(now)
+	switch {
+	case b.remaining <= max(b.maxCost, 1)*reservedQueries:
+		return untilReset
+	case b.remaining < b.limit/slowdownFraction:
+		return untilReset / time.Duration(b.remaining)
+	}
+	return 0
+}
+
+// rateLimitTransport feeds the rate limit headers of every response to the
+// budget
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	budget *rateBudget
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.budget.observeResponse(resp)
+	}
+	return resp, err
+}
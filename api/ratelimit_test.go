@@ -0,0 +1,179 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateBudget_Delay(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	resetAt := now.Add(30 * time.Minute)
+
+	tests := []struct {
+		name      string
+		limit     int
+		remaining int
+		resetAt   time.Time
+		maxCost   int
+		retryAt   time.Time
+		want      time.Duration
+	}{
+		{
+			name: "nothing known",
+			want: 0,
+		},
+		{
+			name:      "plenty remaining",
+			limit:     5000,
+			remaining: 4000,
+			resetAt:   resetAt,
+			want:      0,
+		},
+		{
+			name:      "running low slows down",
+			limit:     5000,
+			remaining: 360,
+			resetAt:   resetAt,
+			want:      5 * time.Second,
+		},
+		{
+			name:      "only the reserve remaining pauses until the reset",
+			limit:     5000,
+			remaining: 40,
+			resetAt:   resetAt,
+			maxCost:   4,
+			want:      30 * time.Minute,
+		},
+		{
+			name:      "window already reset",
+			limit:     5000,
+			remaining: 0,
+			resetAt:   now.Add(-time.Second),
+			want:      0,
+		},
+		{
+			name:    "rate limited",
+			retryAt: now.Add(time.Minute),
+			want:    time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &rateBudget{
+				limit:     tt.limit,
+				remaining: tt.remaining,
+				resetAt:   tt.resetAt,
+				maxCost:   tt.maxCost,
+				retryAt:   tt.retryAt,
+				now:       func() time.Time { return now },
+			}
+			if got := b.delay(); got != tt.want {
+				t.Errorf("delay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateBudget_Observe(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := &rateBudget{now: func() time.Time { return now }}
+
+	b.observeQuery(rateLimitFragment{Limit: 5000, Cost: 3, Remaining: 4000, ResetAt: "2025-01-01T12:30:00Z"})
+	// A response of an earlier request arriving late does not raise the
+	// remaining points
+	b.observe(5000, 4500, now.Add(30*time.Minute))
+	if b.remaining != 4000 || b.maxCost != 3 {
+		t.Errorf("remaining = %d, maxCost = %d, want 4000 and 3", b.remaining, b.maxCost)
+	}
+	// A new window replaces the old one
+	b.observe(5000, 4999, now.Add(90*time.Minute))
+	if b.remaining != 4999 {
+		t.Errorf("remaining = %d, want 4999 after the reset", b.remaining)
+	}
+	if got := b.retryWait(); got != 0 {
+		t.Errorf("retryWait() = %v, want 0", got)
+	}
+
+	// Exhausting the limit waits for the reset
+	b.observe(5000, 0, now.Add(90*time.Minute))
+	if got := b.retryWait(); got != 90*time.Minute {
+		t.Errorf("retryWait() = %v, want 90m after exhausting the limit", got)
+	}
+}
+
+func TestRateBudget_ObserveResponse(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	reset := strconv.FormatInt(now.Add(time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name          string
+		status        int
+		header        http.Header
+		wantRemaining int
+		wantRetryWait time.Duration
+	}{
+		{
+			name:          "rate limit headers",
+			status:        http.StatusOK,
+			header:        http.Header{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"4321"}, "X-Ratelimit-Reset": {reset}},
+			wantRemaining: 4321,
+		},
+		{
+			name:          "secondary rate limit",
+			status:        http.StatusForbidden,
+			header:        http.Header{"Retry-After": {"60"}, "X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"4321"}, "X-Ratelimit-Reset": {reset}},
+			wantRemaining: 4321,
+			wantRetryWait: time.Minute,
+		},
+		{
+			name:          "primary rate limit",
+			status:        http.StatusForbidden,
+			header:        http.Header{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {reset}},
+			wantRemaining: 0,
+			wantRetryWait: time.Hour,
+		},
+		{
+			name:   "Retry-After of a successful response",
+			status: http.StatusOK,
+			header: http.Header{"Retry-After": {"60"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &rateBudget{now: func() time.Time { return now }}
+			b.observeResponse(&http.Response{StatusCode: tt.status, Header: tt.header})
+
+			if b.remaining != tt.wantRemaining {
+				t.Errorf("remaining = %d, want %d", b.remaining, tt.wantRemaining)
+			}
+			if got := b.retryWait(); got != tt.wantRetryWait {
+				t.Errorf("retryWait() = %v, want %v", got, tt.wantRetryWait)
+			}
+		})
+	}
+}
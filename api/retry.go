@@ -21,17 +21,25 @@ THE SOFTWARE.
 */
 package api
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // retryConfig controls the behavior of withRetry.
 type retryConfig struct {
 	maxAttempts int
 	baseDelay   time.Duration
 	maxDelay    time.Duration
-	sleep       func(time.Duration)
+	sleep       func(context.Context, time.Duration) error
 	// retryable returns true for errors that should be retried. If nil,
 	// isRetryable is used (rate limit + transient).
 	retryable func(error) bool
+	// rateLimitWait returns how long the server asked to wait after a rate
+	// limit error, 0 if it did not. Such waits replace the backoff and do
+	// not count as attempts, up to maxRateLimitWaits of them.
+	rateLimitWait     func() time.Duration
+	maxRateLimitWaits int
 }
 
 func defaultRetryConfig() retryConfig {
@@ -39,25 +47,46 @@ func defaultRetryConfig() retryConfig {
 		maxAttempts: 3,
 		baseDelay:   1 * time.Second,
 		maxDelay:    8 * time.Second,
-		sleep:       time.Sleep,
+		sleep:       sleep,
 		retryable:   isRetryable,
+		// Secondary rate limits ask to wait a minute or so, and the
+		// primary rate limit until the window resets
+		maxRateLimitWaits: 5,
 	}
 }
 
-// withRetry runs fn and retries on retryable errors using exponential backoff.
-// It returns the last error if all attempts fail or fn returns a non-retryable error.
-func withRetry(fn func() error, cfg retryConfig) error {
+// withRetry runs fn and retries on retryable errors using exponential backoff,
+// or after the wait the server asked for on rate limit errors. It returns the
+// last error if all attempts fail or fn returns a non-retryable error, and the
+// error of ctx if it is canceled before the next attempt.
+func withRetry(ctx context.Context, fn func() error, cfg retryConfig) error {
 	var err error
 	delay := cfg.baseDelay
+	waits := 0
 	for attempt := 1; attempt <= cfg.maxAttempts; attempt++ {
 		err = fn()
 		if err == nil {
 			return nil
 		}
-		if !cfg.retryable(err) || attempt == cfg.maxAttempts {
+		if !cfg.retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		if IsRateLimit(err) && cfg.rateLimitWait != nil && waits < cfg.maxRateLimitWaits {
+			if wait := cfg.rateLimitWait(); wait > 0 {
+				waits++
+				attempt--
+				if err := cfg.sleep(ctx, wait); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if attempt == cfg.maxAttempts {
+			return err
+		}
+		if err := cfg.sleep(ctx, delay); err != nil {
 			return err
 		}
-		cfg.sleep(delay)
 		delay *= 2
 		if delay > cfg.maxDelay {
 			delay = cfg.maxDelay
@@ -66,6 +95,18 @@ func withRetry(fn func() error, cfg retryConfig) error {
 	return err
 }
 
+// sleep waits for d, returning the error of ctx if it is canceled first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // isRetryable returns true for errors that may succeed on retry: rate limits
 // and transient (5xx, network) failures.
 func isRetryable(err error) bool {
@@ -22,6 +22,7 @@ THE SOFTWARE.
 package api
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -33,8 +34,11 @@ func testRetryConfig() (retryConfig, *[]time.Duration) {
 		maxAttempts: 3,
 		baseDelay:   10 * time.Millisecond,
 		maxDelay:    40 * time.Millisecond,
-		sleep:       func(d time.Duration) { sleeps = append(sleeps, d) },
-		retryable:   isRetryable,
+		sleep: func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		},
+		retryable: isRetryable,
 	}
 	return cfg, &sleeps
 }
@@ -43,7 +47,7 @@ func TestWithRetry_SuccessOnFirstAttempt(t *testing.T) {
 	cfg, sleeps := testRetryConfig()
 	calls := 0
 
-	err := withRetry(func() error {
+	err := withRetry(context.Background(), func() error {
 		calls++
 		return nil
 	}, cfg)
@@ -63,7 +67,7 @@ func TestWithRetry_RetryableThenSuccess(t *testing.T) {
 	cfg, sleeps := testRetryConfig()
 	calls := 0
 
-	err := withRetry(func() error {
+	err := withRetry(context.Background(), func() error {
 		calls++
 		if calls < 3 {
 			return &TransientError{StatusCode: 502}
@@ -93,7 +97,7 @@ func TestWithRetry_NonRetryableErrorStops(t *testing.T) {
 	calls := 0
 	wantErr := &NotFoundError{ResourceType: ResourceTypeRepository}
 
-	err := withRetry(func() error {
+	err := withRetry(context.Background(), func() error {
 		calls++
 		return wantErr
 	}, cfg)
@@ -114,7 +118,7 @@ func TestWithRetry_AllAttemptsFail(t *testing.T) {
 	calls := 0
 	wantErr := &RateLimitError{}
 
-	err := withRetry(func() error {
+	err := withRetry(context.Background(), func() error {
 		calls++
 		return wantErr
 	}, cfg)
@@ -137,11 +141,14 @@ func TestWithRetry_BackoffCappedByMaxDelay(t *testing.T) {
 		maxAttempts: 5,
 		baseDelay:   10 * time.Millisecond,
 		maxDelay:    25 * time.Millisecond,
-		sleep:       func(d time.Duration) { sleeps = append(sleeps, d) },
-		retryable:   isRetryable,
+		sleep: func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		},
+		retryable: isRetryable,
 	}
 
-	_ = withRetry(func() error {
+	_ = withRetry(context.Background(), func() error {
 		return &TransientError{StatusCode: 503}
 	}, cfg)
 
@@ -168,7 +175,7 @@ func TestWithRetry_CustomRetryablePredicate(t *testing.T) {
 
 	// TransientError is normally retryable but the custom predicate excludes
 	// it; the call should run exactly once.
-	err := withRetry(func() error {
+	err := withRetry(context.Background(), func() error {
 		calls++
 		return &TransientError{StatusCode: 503}
 	}, cfg)
@@ -189,7 +196,7 @@ func TestWithRetry_RateLimitOnlyStillRetriesRateLimit(t *testing.T) {
 	cfg.retryable = IsRateLimit
 	calls := 0
 
-	err := withRetry(func() error {
+	err := withRetry(context.Background(), func() error {
 		calls++
 		if calls < 2 {
 			return &RateLimitError{}
@@ -226,3 +233,103 @@ func TestIsRetryable(t *testing.T) {
 		})
 	}
 }
+
+func TestWithRetry_RateLimitWait(t *testing.T) {
+	cfg, sleeps := testRetryConfig()
+	cfg.rateLimitWait = func() time.Duration { return time.Minute }
+	cfg.maxRateLimitWaits = 4
+	calls := 0
+
+	err := withRetry(context.Background(), func() error {
+		calls++
+		if calls <= 4 {
+			return &RateLimitError{}
+		}
+		return nil
+	}, cfg)
+
+	if err != nil {
+		t.Fatalf("withRetry() error = %v, want nil", err)
+	}
+	// The waits the server asked for replace the backoff and are not counted
+	// as attempts
+	wantSleeps := []time.Duration{time.Minute, time.Minute, time.Minute, time.Minute}
+	if len(*sleeps) != len(wantSleeps) {
+		t.Fatalf("sleeps = %v, want %v", *sleeps, wantSleeps)
+	}
+	for i, d := range *sleeps {
+		if d != wantSleeps[i] {
+			t.Errorf("sleeps[%d] = %v, want %v", i, d, wantSleeps[i])
+		}
+	}
+}
+
+func TestWithRetry_RateLimitWaitsExhausted(t *testing.T) {
+	cfg, sleeps := testRetryConfig()
+	cfg.rateLimitWait = func() time.Duration { return time.Minute }
+	cfg.maxRateLimitWaits = 2
+	calls := 0
+
+	err := withRetry(context.Background(), func() error {
+		calls++
+		return &RateLimitError{}
+	}, cfg)
+
+	if !IsRateLimit(err) {
+		t.Errorf("withRetry() error = %v, want rate limit error", err)
+	}
+	// Two waits, then the backoff for the remaining attempts
+	wantSleeps := []time.Duration{time.Minute, time.Minute, 10 * time.Millisecond, 20 * time.Millisecond}
+	if len(*sleeps) != len(wantSleeps) {
+		t.Fatalf("sleeps = %v, want %v", *sleeps, wantSleeps)
+	}
+	for i, d := range *sleeps {
+		if d != wantSleeps[i] {
+			t.Errorf("sleeps[%d] = %v, want %v", i, d, wantSleeps[i])
+		}
+	}
+	if calls != cfg.maxAttempts+cfg.maxRateLimitWaits {
+		t.Errorf("calls = %d, want %d", calls, cfg.maxAttempts+cfg.maxRateLimitWaits)
+	}
+}
+
+func TestWithRetry_Canceled(t *testing.T) {
+	cfg, _ := testRetryConfig()
+	cfg.sleep = sleep
+	cfg.baseDelay = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	// The backoff is cut short once ctx is canceled, and no attempt follows
+	time.AfterFunc(10*time.Millisecond, cancel)
+	err := withRetry(ctx, func() error {
+		calls++
+		return &TransientError{StatusCode: 502}
+	}, cfg)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("withRetry() error = %v, want %v", err, context.Canceled)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestWithRetry_CanceledNotRetried(t *testing.T) {
+	cfg, sleeps := testRetryConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+
+	err := withRetry(ctx, func() error {
+		calls++
+		return &TransientError{StatusCode: 502}
+	}, cfg)
+
+	if !IsTransient(err) {
+		t.Errorf("withRetry() error = %v, want TransientError", err)
+	}
+	if calls != 1 || len(*sleeps) != 0 {
+		t.Errorf("calls = %d, sleeps = %v, want 1 call and no sleep", calls, *sleeps)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tnagatomi/gh-fuda/executor"
+)
+
+// NewApplyCmd initializes the apply command
+func NewApplyCmd() *cobra.Command {
+	var skipConfirm bool
+
+	var applyCmd = &cobra.Command{
+		Use:   "apply <plan-file>",
+		Short: "Apply a plan saved with the --out option of another command",
+		Long: `Apply a plan saved with the --out option of create, delete, sync, empty,
+merge, rename, copy or apply-config.
+
+The plan records the labels each repository had when it was made. If the labels
+of any repository changed since then, nothing is applied and a new plan must be made.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plan, err := executor.ReadPlan(args[0])
+			if err != nil {
+				return err
+			}
+
+			in := cmd.InOrStdin()
+			out := cmd.OutOrStdout()
+
+			if !dryRun && !skipConfirm {
+				if err := plan.Print(out); err != nil {
+					return err
+				}
+				confirmed, err := confirm(cmd.Context(), in, out)
+				if err != nil {
+					return fmt.Errorf("failed to confirm execution: %v", err)
+				}
+				if !confirmed {
+					_, _ = fmt.Fprintf(out, "Canceled execution\n")
+					return nil
+				}
+			}
+
+			e, err := newExecutor(dryRun, nil)
+			if err != nil {
+				return fmt.Errorf("failed to create executor: %v", err)
+			}
+
+			err = e.ApplySaved(cmd.Context(), out, plan)
+			if err != nil {
+				return fmt.Errorf("failed to apply plan: %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	applyCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Do not prompt for confirmation")
+
+	return applyCmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewApplyCmd())
+}
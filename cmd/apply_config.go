@@ -0,0 +1,115 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tnagatomi/gh-fuda/executor"
+	"github.com/tnagatomi/gh-fuda/parser"
+)
+
+// defaultConfigPath is the config file apply-config reads when none is given
+const defaultConfigPath = ".fuda.yaml"
+
+// NewApplyConfigCmd initializes the apply-config command
+func NewApplyConfigCmd() *cobra.Command {
+	var skipConfirm bool
+	var planOut string
+
+	var applyConfigCmd = &cobra.Command{
+		Use:   "apply-config [config-file]",
+		Short: "Sync the labels of repositories as described by a config file",
+		Long: `Sync the labels of repositories as described by a config file (default .fuda.yaml).
+
+The config file defines named label sets and targets giving label sets to groups
+of repositories. A repository selected by several targets gets the labels of all
+of them, and a target can override labels of its sets or add labels of its own.
+Every repository is then synced with its own labels, as with the sync command.
+
+Repositories are selected by the config file, so --repos, --repos-file, --org and
+--repo-query cannot be used.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if repos != "" || reposFile != "" || len(owners) > 0 || repoQuery != "" {
+				return errors.New("--repos, --repos-file, --org and --repo-query cannot be used with apply-config")
+			}
+
+			path := defaultConfigPath
+			if len(args) > 0 {
+				path = args[0]
+			}
+			cfg, err := parser.ConfigFromYAML(path)
+			if err != nil {
+				return fmt.Errorf("failed to parse config file %s: %v", path, err)
+			}
+
+			mode, err := executor.ConfigSyncMode(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to parse config file %s: %v", path, err)
+			}
+
+			repoExcludes, err := parseExcludesInput()
+			if err != nil {
+				return err
+			}
+
+			in := cmd.InOrStdin()
+			out := cmd.OutOrStdout()
+
+			if !dryRun && planOut == "" && !skipConfirm {
+				confirmed, err := confirm(cmd.Context(), in, out)
+				if err != nil {
+					return fmt.Errorf("failed to confirm execution: %v", err)
+				}
+				if !confirmed {
+					_, _ = fmt.Fprintf(out, "Canceled execution\n")
+					return nil
+				}
+			}
+
+			e, err := newExecutor(dryRun, repoExcludes)
+			if err != nil {
+				return fmt.Errorf("failed to create executor: %v", err)
+			}
+			e.SetPlanPath(planOut)
+
+			err = e.ApplyConfig(cmd.Context(), out, cfg, mode)
+			if err != nil {
+				return fmt.Errorf("failed to apply config: %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	applyConfigCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Do not prompt for confirmation")
+	applyConfigCmd.Flags().StringVar(&planOut, "out", "", "Save the plan to the specified file instead of applying it (run it later with the apply command)")
+
+	return applyConfigCmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewApplyConfigCmd())
+}
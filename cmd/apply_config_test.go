@@ -0,0 +1,97 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyConfigCmd_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		config  string
+		wantErr string
+	}{
+		{
+			name:    "repos given",
+			args:    []string{"apply-config", "-R", "owner/repo"},
+			wantErr: "--repos, --repos-file, --org and --repo-query cannot be used with apply-config",
+		},
+		{
+			name:    "missing config file",
+			args:    []string{"apply-config", "testdata/missing.yaml"},
+			wantErr: "failed to parse config file testdata/missing.yaml",
+		},
+		{
+			name:    "too many config files",
+			args:    []string{"apply-config", "a.yaml", "b.yaml"},
+			wantErr: "accepts at most 1 arg(s)",
+		},
+		{
+			name: "invalid mode",
+			args: []string{"apply-config"},
+			config: `mode: mirror
+label_sets:
+  common: [{name: bug}]
+targets:
+  - repos: [acme/*]
+    sets: [common]
+`,
+			wantErr: `invalid sync mode "mirror"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos = ""
+			dryRun = false
+
+			args := tt.args
+			if tt.config != "" {
+				path := filepath.Join(t.TempDir(), ".fuda.yaml")
+				if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				args = append(args, path)
+			}
+
+			var out bytes.Buffer
+			rootCmd.SetArgs(args)
+			rootCmd.SetIn(strings.NewReader(""))
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&out)
+
+			err := rootCmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Execute() error = %v, want error containing %q", err, tt.wantErr)
+			}
+			if strings.Contains(out.String(), "Are you sure") {
+				t.Errorf("Execute() prompted for confirmation before failing")
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tnagatomi/gh-fuda/executor"
+	"github.com/tnagatomi/gh-fuda/parser"
+)
+
+// NewCopyCmd initializes the copy command
+func NewCopyCmd() *cobra.Command {
+	var from string
+	var opts executor.CopyOptions
+	var skipConfirm bool
+	var planOut string
+
+	var copyCmd = &cobra.Command{
+		Use:   "copy",
+		Short: "Copy the labels of a source repository to the specified repositories",
+		Long: `Copy the labels of a source repository to the specified repositories.
+
+By default the labels are created as with the create command; use --force to
+also update labels that already exist. With --sync, the labels of the target
+repositories are synced with the copied labels instead, as with the sync command.
+
+--include and --exclude select the labels to copy by name, using glob patterns
+where * matches any sequence of characters except '/', ignoring case.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Sync && opts.Force {
+				return errors.New("--sync and --force cannot be used together")
+			}
+
+			source, err := parser.Repo(from)
+			if err != nil {
+				return fmt.Errorf("failed to parse from option: %v", err)
+			}
+			if len(source) != 1 {
+				return fmt.Errorf("--from must be a single repository")
+			}
+
+			repoList, repoExcludes, err := parseReposInput(cmd.InOrStdin(), repos)
+			if err != nil {
+				return err
+			}
+
+			in := cmd.InOrStdin()
+			out := cmd.OutOrStdout()
+
+			if opts.Sync && !dryRun && planOut == "" && !skipConfirm {
+				confirmed, err := confirm(cmd.Context(), in, out)
+				if err != nil {
+					return fmt.Errorf("failed to confirm execution: %v", err)
+				}
+				if !confirmed {
+					_, _ = fmt.Fprintf(out, "Canceled execution\n")
+					return nil
+				}
+			}
+
+			e, err := newExecutor(dryRun, repoExcludes)
+			if err != nil {
+				return fmt.Errorf("failed to create executor: %v", err)
+			}
+			e.SetPlanPath(planOut)
+
+			err = e.Copy(cmd.Context(), out, source[0], repoList, opts)
+			if err != nil {
+				return fmt.Errorf("failed to copy labels: %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	copyCmd.Flags().StringVar(&from, "from", "", "Source repository to copy labels from (e.g., owner/template)")
+	copyCmd.Flags().StringSliceVar(&opts.Include, "include", nil, "Only copy labels whose name matches one of these patterns (e.g., 'area/*,bug')")
+	copyCmd.Flags().StringSliceVar(&opts.Exclude, "exclude", nil, "Do not copy labels whose name matches one of these patterns")
+	copyCmd.Flags().BoolVar(&opts.Sync, "sync", false, "Sync the labels of the target repositories with the copied labels, deleting the others matching --include and --exclude")
+	copyCmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Update the label color and description if label already exists")
+	copyCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Do not prompt for confirmation")
+	copyCmd.Flags().StringVar(&planOut, "out", "", "Save the plan to the specified file instead of applying it (run it later with the apply command)")
+
+	err := copyCmd.MarkFlagRequired("from")
+	if err != nil {
+		fmt.Printf("Failed to mark flag required: %v\n", err)
+	}
+
+	return copyCmd
+}
+
+func init() {
+	copyCmd := NewCopyCmd()
+	rootCmd.AddCommand(copyCmd)
+}
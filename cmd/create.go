@@ -25,13 +25,12 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/tnagatomi/gh-fuda/executor"
-	"github.com/tnagatomi/gh-fuda/parser"
 )
 
 // NewCreateCmd initialize the create command
 func NewCreateCmd() *cobra.Command {
 	var force bool
+	var planOut string
 
 	var createCmd = &cobra.Command{
 		Use:   "create",
@@ -42,18 +41,19 @@ func NewCreateCmd() *cobra.Command {
 				return err
 			}
 
-			repoList, err := parser.Repo(repos)
+			repoList, repoExcludes, err := parseReposInput(cmd.InOrStdin(), repos)
 			if err != nil {
-				return fmt.Errorf("failed to parse repos option: %v", err)
+				return err
 			}
 
-			e, err := executor.NewExecutor(dryRun)
+			e, err := newExecutor(dryRun, repoExcludes)
 			if err != nil {
 				return fmt.Errorf("failed to create executor: %v", err)
 			}
+			e.SetPlanPath(planOut)
 
 			out := cmd.OutOrStdout()
-			err = e.Create(out, repoList, labelList, force)
+			err = e.Create(cmd.Context(), out, repoList, labelList, force)
 			if err != nil {
 				return fmt.Errorf("failed to create labels: %v", err)
 			}
@@ -63,6 +63,7 @@ func NewCreateCmd() *cobra.Command {
 	}
 
 	createCmd.Flags().BoolVarP(&force, "force", "f", false, "Update the label color and description if label already exists")
+	createCmd.Flags().StringVar(&planOut, "out", "", "Save the plan to the specified file instead of applying it (run it later with the apply command)")
 
 	return createCmd
 }
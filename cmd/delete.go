@@ -26,22 +26,21 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/tnagatomi/gh-fuda/executor"
-	"github.com/tnagatomi/gh-fuda/parser"
 )
 
 // NewDeleteCmd represents the delete command
 func NewDeleteCmd() *cobra.Command {
 	var skipConfirm bool
 	var forceDeprecated bool
+	var planOut string
 
 	var deleteCmd = &cobra.Command{
 		Use:   "delete",
 		Short: "Delete specified labels from the specified repositories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			repoList, err := parser.Repo(repos)
+			repoList, repoExcludes, err := parseReposInput(cmd.InOrStdin(), repos)
 			if err != nil {
-				return fmt.Errorf("failed to parse repos option: %v", err)
+				return err
 			}
 
 			labelList := strings.Split(labels, ",")
@@ -49,8 +48,8 @@ func NewDeleteCmd() *cobra.Command {
 			in := cmd.InOrStdin()
 			out := cmd.OutOrStdout()
 
-			if !dryRun && !skipConfirm && !forceDeprecated {
-				confirmed, err := confirm(in, out)
+			if !dryRun && planOut == "" && !skipConfirm && !forceDeprecated {
+				confirmed, err := confirm(cmd.Context(), in, out)
 				if err != nil {
 					return fmt.Errorf("failed to confirm execution: %v", err)
 				}
@@ -60,12 +59,13 @@ func NewDeleteCmd() *cobra.Command {
 				}
 			}
 
-			e, err := executor.NewExecutor(dryRun)
+			e, err := newExecutor(dryRun, repoExcludes)
 			if err != nil {
 				return fmt.Errorf("failed to create executor: %v", err)
 			}
+			e.SetPlanPath(planOut)
 
-			err = e.Delete(out, repoList, labelList)
+			err = e.Delete(cmd.Context(), out, repoList, labelList)
 			if err != nil {
 				return fmt.Errorf("failed to delete labels: %v", err)
 			}
@@ -78,6 +78,8 @@ func NewDeleteCmd() *cobra.Command {
 	deleteCmd.Flags().BoolVar(&forceDeprecated, "force", false, "Do not prompt for confirmation")
 	_ = deleteCmd.Flags().MarkDeprecated("force", "use -y/--yes instead")
 
+	deleteCmd.Flags().StringVar(&planOut, "out", "", "Save the plan to the specified file instead of applying it (run it later with the apply command)")
+
 	return deleteCmd
 }
 
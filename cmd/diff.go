@@ -0,0 +1,75 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewDiffCmd represents the diff command
+func NewDiffCmd() *cobra.Command {
+	var diffCmd = &cobra.Command{
+		Use:   "diff",
+		Short: "Show how the labels in the specified repositories differ from the specified labels",
+		Long: `Show how the labels in the specified repositories differ from the specified labels.
+
+For each repository, labels that are missing, extra, or different are listed.
+Nothing is changed. The command exits with a non-zero status if the labels of
+any repository differ, so that it can be used as a check in CI.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			labelList, err := parseLabelsInput(labels, jsonPath, yamlPath)
+			if err != nil {
+				return err
+			}
+
+			repoList, repoExcludes, err := parseReposInput(cmd.InOrStdin(), repos)
+			if err != nil {
+				return err
+			}
+
+			e, err := newExecutor(false, repoExcludes)
+			if err != nil {
+				return fmt.Errorf("failed to create executor: %v", err)
+			}
+
+			out := cmd.OutOrStdout()
+			err = e.Diff(cmd.Context(), out, repoList, labelList)
+			if err != nil {
+				return fmt.Errorf("failed to diff labels: %v", err)
+			}
+
+			return nil
+		},
+	}
+	return diffCmd
+}
+
+func init() {
+	diffCmd := NewDiffCmd()
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVarP(&labels, "labels", "l", "", "Specify the labels to compare with in the format of 'label1:color1:description1[,label2:color2:description2,...]' (description can be omitted)")
+	diffCmd.Flags().StringVar(&jsonPath, "json", "", "Specify the path to a JSON file containing labels to compare with")
+	diffCmd.Flags().StringVar(&yamlPath, "yaml", "", "Specify the path to a YAML file containing labels to compare with")
+}
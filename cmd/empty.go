@@ -25,29 +25,28 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/tnagatomi/gh-fuda/executor"
-	"github.com/tnagatomi/gh-fuda/parser"
 )
 
 // NewEmptyCmd represents the empty command
 func NewEmptyCmd() *cobra.Command {
 	var skipConfirm bool
 	var forceDeprecated bool
+	var planOut string
 
 	var emptyCmd = &cobra.Command{
 		Use:   "empty",
 		Short: "Delete all labels from the specified repositories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			repoList, err := parser.Repo(repos)
+			repoList, repoExcludes, err := parseReposInput(cmd.InOrStdin(), repos)
 			if err != nil {
-				return fmt.Errorf("failed to parse repos option: %v", err)
+				return err
 			}
 
 			in := cmd.InOrStdin()
 			out := cmd.OutOrStdout()
 
-			if !dryRun && !skipConfirm && !forceDeprecated {
-				confirmed, err := confirm(in, out)
+			if !dryRun && planOut == "" && !skipConfirm && !forceDeprecated {
+				confirmed, err := confirm(cmd.Context(), in, out)
 				if err != nil {
 					return fmt.Errorf("failed to confirm execution: %v", err)
 				}
@@ -57,12 +56,13 @@ func NewEmptyCmd() *cobra.Command {
 				}
 			}
 
-			e, err := executor.NewExecutor(dryRun)
+			e, err := newExecutor(dryRun, repoExcludes)
 			if err != nil {
 				return fmt.Errorf("failed to create executor: %v", err)
 			}
+			e.SetPlanPath(planOut)
 
-			err = e.Empty(out, repoList)
+			err = e.Empty(cmd.Context(), out, repoList)
 			if err != nil {
 				return fmt.Errorf("failed to empty labels: %v", err)
 			}
@@ -75,6 +75,8 @@ func NewEmptyCmd() *cobra.Command {
 	emptyCmd.Flags().BoolVar(&forceDeprecated, "force", false, "Do not prompt for confirmation")
 	_ = emptyCmd.Flags().MarkDeprecated("force", "use -y/--yes instead")
 
+	emptyCmd.Flags().StringVar(&planOut, "out", "", "Save the plan to the specified file instead of applying it (run it later with the apply command)")
+
 	return emptyCmd
 }
 
@@ -0,0 +1,89 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/tnagatomi/gh-fuda/executor"
+)
+
+// NewExportCmd represents the export command
+func NewExportCmd() *cobra.Command {
+	var format string
+	var output string
+	var split bool
+
+	var exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export the labels of the specified repositories to a JSON or YAML file",
+		Long: `Export the labels of the specified repositories to a JSON or YAML file.
+
+The file can be read back with the --json or --yaml option of create, sync and diff.
+The labels of multiple repositories are merged into a single file, with labels
+that differ between repositories annotated, unless --split is given.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoList, repoExcludes, err := parseReposInput(cmd.InOrStdin(), repos)
+			if err != nil {
+				return err
+			}
+
+			formatName := format
+			if formatName == "" {
+				formatName = string(executor.ExportFormatYAML)
+				if !split && filepath.Ext(output) == ".json" {
+					formatName = string(executor.ExportFormatJSON)
+				}
+			}
+			exportFormat, err := executor.ParseExportFormat(formatName)
+			if err != nil {
+				return err
+			}
+
+			e, err := newExecutor(false, repoExcludes)
+			if err != nil {
+				return fmt.Errorf("failed to create executor: %v", err)
+			}
+
+			out := cmd.OutOrStdout()
+			err = e.Export(cmd.Context(), out, cmd.ErrOrStderr(), repoList, exportFormat, output, split)
+			if err != nil {
+				return fmt.Errorf("failed to export labels: %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	exportCmd.Flags().StringVar(&format, "format", "", "Format of the exported labels: yaml or json (default: inferred from --output, otherwise yaml)")
+	exportCmd.Flags().StringVarP(&output, "output", "o", "", "Write the labels to the specified file instead of standard output (with --split, the directory to write files to)")
+	exportCmd.Flags().BoolVar(&split, "split", false, "Write the labels of each repository to its own file, <output>/<owner>/<repo>.<format>, under <output>/<host> for other hosts than the default one")
+
+	return exportCmd
+}
+
+func init() {
+	exportCmd := NewExportCmd()
+	rootCmd.AddCommand(exportCmd)
+}
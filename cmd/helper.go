@@ -23,20 +23,46 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
+	"github.com/tnagatomi/gh-fuda/executor"
 	"github.com/tnagatomi/gh-fuda/option"
 	"github.com/tnagatomi/gh-fuda/parser"
 )
 
-// confirm asks user to really execute a command
-func confirm(in io.Reader, out io.Writer) (bool, error) {
+// confirm asks user to really execute a command. Canceling ctx, as Ctrl-C
+// does, answers no.
+func confirm(ctx context.Context, in io.Reader, out io.Writer) (bool, error) {
 	_, _ = fmt.Fprintf(out, "Are you sure you want to do this? (y/n): ")
 
-	reader := bufio.NewReader(in)
-	s, err := reader.ReadString('\n')
+	type answer struct {
+		s   string
+		err error
+	}
+	answered := make(chan answer, 1)
+	go func() {
+		s, err := bufio.NewReader(in).ReadString('\n')
+		answered <- answer{s, err}
+	}()
+
+	var s string
+	var err error
+	select {
+	case <-ctx.Done():
+		_, _ = fmt.Fprintln(out)
+		return false, nil
+	case a := <-answered:
+		s, err = a.s, a.err
+	}
+	if errors.Is(err, io.EOF) {
+		// Nothing left to read, e.g. when repositories were piped in
+		return false, errors.New("failed to read: no answer given (use --yes to skip confirmation)")
+	}
 	if err != nil {
 		return false, fmt.Errorf("failed to read: %v", err)
 	}
@@ -46,6 +72,138 @@ func confirm(in io.Reader, out io.Writer) (bool, error) {
 	return false, nil
 }
 
+// parseReposInput parses the --repos and --repos-file flags and adds an
+// OWNER/* wildcard for each owner of the --org flag. Every command operating
+// on repositories requires one of them or --repo-query. A value of "-" reads
+// the repositories from in. It also returns the patterns of --exclude-repos
+// and the exclusions of the repository file, to be passed to newExecutor.
+func parseReposInput(in io.Reader, repos string) ([]option.Repo, option.RepoPatterns, error) {
+	if repos == "" && reposFile == "" && len(owners) == 0 && repoQuery == "" {
+		return nil, nil, errors.New(`required flag(s) "repos", "repos-file", "org" or "repo-query" not set`)
+	}
+	if repos == "-" && reposFile == "-" {
+		return nil, nil, errors.New(`--repos and --repos-file cannot both read from stdin`)
+	}
+
+	var repoList []option.Repo
+	var repoExcludes option.RepoPatterns
+	if repos == "-" {
+		var err error
+		repoList, repoExcludes, err = parser.RepoLines(in)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse repos from stdin: %v", err)
+		}
+	} else if repos != "" {
+		var err error
+		repoList, err = parser.Repo(repos)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse repos option: %v", err)
+		}
+	}
+
+	patterns, err := parseExcludesInput()
+	if err != nil {
+		return nil, nil, err
+	}
+	repoExcludes = append(repoExcludes, patterns...)
+
+	if reposFile != "" {
+		fileRepos, fileExcludes, err := readReposFile(in, reposFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		repoList = append(repoList, fileRepos...)
+		repoExcludes = append(repoExcludes, fileExcludes...)
+	}
+
+	for _, owner := range owners {
+		if owner == "" || strings.ContainsAny(owner, "/*") {
+			return nil, nil, fmt.Errorf("invalid owner: %q", owner)
+		}
+		repoList = append(repoList, option.Repo{Owner: owner, Repo: option.RepoWildcard})
+	}
+
+	if len(repoList) == 0 && repoQuery == "" {
+		return nil, nil, errors.New("no repositories specified")
+	}
+
+	if !repoFilter.Narrows(repoList, repoQuery) {
+		return nil, nil, errors.New("--topic and --language need --org, OWNER/* in --repos, or --repo-query")
+	}
+	return repoList, repoExcludes, nil
+}
+
+// parseExcludesInput parses the --exclude-repos flag
+func parseExcludesInput() (option.RepoPatterns, error) {
+	if excludes == "" {
+		return nil, nil
+	}
+	patterns, err := parser.RepoPatterns(excludes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exclude-repos option: %v", err)
+	}
+	return patterns, nil
+}
+
+// readReposFile reads the repositories listed in the file at path, or in in
+// if path is "-"
+func readReposFile(in io.Reader, path string) ([]option.Repo, option.RepoPatterns, error) {
+	if path == "-" {
+		repoList, excludes, err := parser.RepoLines(in)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse repos from stdin: %v", err)
+		}
+		return repoList, excludes, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open repos file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	repoList, excludes, err := parser.RepoLines(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse repos file %s: %v", path, err)
+	}
+	return repoList, excludes, nil
+}
+
+// newExecutor creates an executor with the settings of the global flags,
+// excluding the repositories matching repoExcludes
+func newExecutor(dryRun bool, repoExcludes option.RepoPatterns) (*executor.Executor, error) {
+	if batchSize < 1 {
+		return nil, fmt.Errorf("--batch-size must be at least 1, got %d", batchSize)
+	}
+	if concurrency < 1 {
+		return nil, fmt.Errorf("--concurrency must be at least 1, got %d", concurrency)
+	}
+	if ownerConcurrency < 0 {
+		return nil, fmt.Errorf("--owner-concurrency cannot be negative, got %d", ownerConcurrency)
+	}
+	if maxMutationsPerMinute < 0 {
+		return nil, fmt.Errorf("--max-mutations-per-minute cannot be negative, got %d", maxMutationsPerMinute)
+	}
+	visibilities, err := parser.Visibilities(repoFilter.SkipVisibilities)
+	if err != nil {
+		return nil, fmt.Errorf("--skip-visibility: %v", err)
+	}
+	filter := repoFilter
+	filter.SkipVisibilities = visibilities
+	e, err := executor.NewExecutor(dryRun, hostname)
+	if err != nil {
+		return nil, err
+	}
+	e.SetBatchSize(batchSize)
+	e.SetConcurrency(concurrency)
+	e.SetOwnerConcurrency(ownerConcurrency)
+	e.SetMaxMutationsPerMinute(maxMutationsPerMinute)
+	e.SetRepoFilter(filter)
+	e.SetRepoQuery(repoQuery)
+	e.SetRepoExcludes(repoExcludes)
+	return e, nil
+}
+
 // parseLabelsInput validates and parses label input from various sources (--labels, --json, or --yaml flags)
 func parseLabelsInput(labels, jsonPath, yamlPath string) ([]option.Label, error) {
 	// Check that only one input method is specified
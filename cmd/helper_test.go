@@ -0,0 +1,231 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tnagatomi/gh-fuda/executor"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+func TestParseReposInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		repos    string
+		stdin    string
+		file     string
+		owners   []string
+		query    string
+		topics   []string
+		want     []option.Repo
+		exclude  string
+		excludes option.RepoPatterns
+		wantErr  bool
+	}{
+		{
+			name:  "repos only",
+			repos: "owner/repo1,owner/*",
+			want:  []option.Repo{{Owner: "owner", Repo: "repo1"}, {Owner: "owner", Repo: "*"}},
+		},
+		{
+			name:   "owners are added as wildcards",
+			repos:  "owner/repo1",
+			owners: []string{"org1", "org2"},
+			want: []option.Repo{
+				{Owner: "owner", Repo: "repo1"},
+				{Owner: "org1", Repo: "*"},
+				{Owner: "org2", Repo: "*"},
+			},
+		},
+		{
+			name:   "owners only",
+			owners: []string{"org1"},
+			want:   []option.Repo{{Owner: "org1", Repo: "*"}},
+		},
+		{
+			name:  "search query only",
+			query: "org:acme",
+		},
+		{
+			name:    "neither repos, owners nor search query",
+			wantErr: true,
+		},
+		{
+			name:   "topic with owners",
+			owners: []string{"org1"},
+			topics: []string{"backend"},
+			want:   []option.Repo{{Owner: "org1", Repo: "*"}},
+		},
+		{
+			name:    "topic with explicit repositories only",
+			repos:   "owner/repo1",
+			topics:  []string{"backend"},
+			wantErr: true,
+		},
+		{
+			name:     "repos from stdin",
+			repos:    "-",
+			stdin:    "# services\nowner/repo1\n\n  https://github.com/owner/repo2.git  # main\n!owner/legacy\n",
+			want:     []option.Repo{{Owner: "owner", Repo: "repo1"}, {Host: "github.com", Owner: "owner", Repo: "repo2"}},
+			excludes: option.RepoPatterns{"owner/legacy"},
+		},
+		{
+			name:     "repos file with repos",
+			repos:    "owner/repo1",
+			file:     "org/*\n!org/legacy\n",
+			want:     []option.Repo{{Owner: "owner", Repo: "repo1"}, {Owner: "org", Repo: "*"}},
+			excludes: option.RepoPatterns{"org/legacy"},
+		},
+		{
+			name:     "exclude-repos with repos from stdin",
+			repos:    "-",
+			stdin:    "org/*\n!org/legacy-*\n",
+			exclude:  "org/sandbox, */archive-*",
+			want:     []option.Repo{{Owner: "org", Repo: "*"}},
+			excludes: option.RepoPatterns{"org/legacy-*", "org/sandbox", "*/archive-*"},
+		},
+		{
+			name:    "invalid exclude-repos pattern",
+			repos:   "org/*",
+			exclude: "org/legacy-[",
+			wantErr: true,
+		},
+		{
+			name:    "repos file with only exclusions",
+			file:    "!org/legacy\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid line in repos file",
+			file:    "owner/repo1\nowner\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid owner",
+			owners:  []string{"org1/repo"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owners = tt.owners
+			repoQuery = tt.query
+			repoFilter.Topics = tt.topics
+			excludes = tt.exclude
+			if tt.file != "" {
+				reposFile = filepath.Join(t.TempDir(), "repos.txt")
+				if err := os.WriteFile(reposFile, []byte(tt.file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			defer func() {
+				owners = nil
+				repoQuery = ""
+				repoFilter.Topics = nil
+				reposFile = ""
+				excludes = ""
+			}()
+
+			got, gotExcludes, err := parseReposInput(strings.NewReader(tt.stdin), tt.repos)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReposInput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseReposInput() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.excludes, gotExcludes); diff != "" {
+				t.Errorf("parseReposInput() excludes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewExecutor_InvalidFlags(t *testing.T) {
+	tests := []struct {
+		name                  string
+		batchSize             int
+		concurrency           int
+		ownerConcurrency      int
+		maxMutationsPerMinute int
+		skipVisibilities      []string
+		wantErr               string
+	}{
+		{
+			name:        "zero batch size",
+			batchSize:   0,
+			concurrency: 5,
+			wantErr:     "--batch-size must be at least 1, got 0",
+		},
+		{
+			name:        "zero concurrency",
+			batchSize:   50,
+			concurrency: 0,
+			wantErr:     "--concurrency must be at least 1, got 0",
+		},
+		{
+			name:             "negative owner concurrency",
+			batchSize:        50,
+			concurrency:      5,
+			ownerConcurrency: -1,
+			wantErr:          "--owner-concurrency cannot be negative, got -1",
+		},
+		{
+			name:                  "negative mutations per minute",
+			batchSize:             50,
+			concurrency:           5,
+			maxMutationsPerMinute: -1,
+			wantErr:               "--max-mutations-per-minute cannot be negative, got -1",
+		},
+		{
+			name:             "unknown visibility",
+			batchSize:        50,
+			concurrency:      5,
+			skipVisibilities: []string{"Private", "privte"},
+			wantErr:          `--skip-visibility: invalid visibility "privte": must be one of public, private, internal`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchSize, concurrency, ownerConcurrency, maxMutationsPerMinute = tt.batchSize, tt.concurrency, tt.ownerConcurrency, tt.maxMutationsPerMinute
+			repoFilter.SkipVisibilities = tt.skipVisibilities
+			defer func() {
+				batchSize, concurrency, ownerConcurrency, maxMutationsPerMinute = executor.DefaultBatchSize, executor.WorkerPoolSize, 0, 0
+				repoFilter.SkipVisibilities = nil
+			}()
+
+			_, err := newExecutor(true, nil)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("newExecutor() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -25,8 +25,6 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/tnagatomi/gh-fuda/executor"
-	"github.com/tnagatomi/gh-fuda/parser"
 )
 
 // NewListCmd initialize the list command
@@ -35,18 +33,18 @@ func NewListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List existing labels from the specified repositories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			repoList, err := parser.Repo(repos)
+			repoList, repoExcludes, err := parseReposInput(cmd.InOrStdin(), repos)
 			if err != nil {
-				return fmt.Errorf("failed to parse repos option: %v", err)
+				return err
 			}
 
-			e, err := executor.NewExecutor(false)
+			e, err := newExecutor(false, repoExcludes)
 			if err != nil {
 				return fmt.Errorf("failed to create executor: %v", err)
 			}
 
 			out := cmd.OutOrStdout()
-			err = e.List(out, repoList)
+			err = e.List(cmd.Context(), out, repoList)
 			if err != nil {
 				return fmt.Errorf("failed to list labels: %v", err)
 			}
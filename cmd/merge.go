@@ -26,8 +26,6 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/tnagatomi/gh-fuda/executor"
-	"github.com/tnagatomi/gh-fuda/parser"
 )
 
 var (
@@ -38,6 +36,8 @@ var (
 
 // NewMergeCmd represents the merge command
 func NewMergeCmd() *cobra.Command {
+	var planOut string
+
 	var mergeCmd = &cobra.Command{
 		Use:   "merge",
 		Short: "Merge a source label into a target label across repositories",
@@ -54,16 +54,16 @@ Both the source (--from) and target (--to) labels must exist in each repository.
 				return fmt.Errorf("source and target labels must be different")
 			}
 
-			repoList, err := parser.Repo(repos)
+			repoList, repoExcludes, err := parseReposInput(cmd.InOrStdin(), repos)
 			if err != nil {
-				return fmt.Errorf("failed to parse repos option: %v", err)
+				return err
 			}
 
 			in := cmd.InOrStdin()
 			out := cmd.OutOrStdout()
 
-			if !dryRun && !skipConfirm {
-				confirmed, err := confirm(in, out)
+			if !dryRun && planOut == "" && !skipConfirm {
+				confirmed, err := confirm(cmd.Context(), in, out)
 				if err != nil {
 					return fmt.Errorf("failed to confirm execution: %v", err)
 				}
@@ -73,12 +73,13 @@ Both the source (--from) and target (--to) labels must exist in each repository.
 				}
 			}
 
-			e, err := executor.NewExecutor(dryRun)
+			e, err := newExecutor(dryRun, repoExcludes)
 			if err != nil {
 				return fmt.Errorf("failed to create executor: %v", err)
 			}
+			e.SetPlanPath(planOut)
 
-			err = e.Merge(out, repoList, fromLabel, toLabel)
+			err = e.Merge(cmd.Context(), out, repoList, fromLabel, toLabel)
 			if err != nil {
 				return fmt.Errorf("failed to merge labels: %v", err)
 			}
@@ -86,6 +87,9 @@ Both the source (--from) and target (--to) labels must exist in each repository.
 			return nil
 		},
 	}
+
+	mergeCmd.Flags().StringVar(&planOut, "out", "", "Save the plan to the specified file instead of applying it (run it later with the apply command)")
+
 	return mergeCmd
 }
 
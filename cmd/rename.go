@@ -0,0 +1,116 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tnagatomi/gh-fuda/parser"
+)
+
+// NewRenameCmd initializes the rename command
+func NewRenameCmd() *cobra.Command {
+	var from, to, color, description string
+	var skipConfirm bool
+	var planOut string
+
+	var renameCmd = &cobra.Command{
+		Use:   "rename",
+		Short: "Rename a label across repositories",
+		Long: `Rename a label across repositories.
+
+The label is renamed in place, so it keeps its color, description and all of its
+issues, pull requests and discussions. Use --color and --description to change
+them at the same time.
+
+In repositories where the new name already exists, the label is merged into the
+existing one instead, as with the merge command.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == to {
+				return fmt.Errorf("source and target labels must be different")
+			}
+
+			if color != "" {
+				var err error
+				color, err = parser.Color(color)
+				if err != nil {
+					return err
+				}
+			}
+
+			repoList, repoExcludes, err := parseReposInput(cmd.InOrStdin(), repos)
+			if err != nil {
+				return err
+			}
+
+			in := cmd.InOrStdin()
+			out := cmd.OutOrStdout()
+
+			if !dryRun && planOut == "" && !skipConfirm {
+				confirmed, err := confirm(cmd.Context(), in, out)
+				if err != nil {
+					return fmt.Errorf("failed to confirm execution: %v", err)
+				}
+				if !confirmed {
+					_, _ = fmt.Fprintf(out, "Canceled execution\n")
+					return nil
+				}
+			}
+
+			e, err := newExecutor(dryRun, repoExcludes)
+			if err != nil {
+				return fmt.Errorf("failed to create executor: %v", err)
+			}
+			e.SetPlanPath(planOut)
+
+			err = e.Rename(cmd.Context(), out, repoList, from, to, color, description)
+			if err != nil {
+				return fmt.Errorf("failed to rename label: %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	renameCmd.Flags().StringVar(&from, "from", "", "Current name of the label")
+	renameCmd.Flags().StringVar(&to, "to", "", "New name of the label")
+	renameCmd.Flags().StringVar(&color, "color", "", "Set a new color for the label (e.g., a2eeef)")
+	renameCmd.Flags().StringVar(&description, "description", "", "Set a new description for the label")
+	renameCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Do not prompt for confirmation")
+	renameCmd.Flags().StringVar(&planOut, "out", "", "Save the plan to the specified file instead of applying it (run it later with the apply command)")
+
+	err := renameCmd.MarkFlagRequired("from")
+	if err != nil {
+		fmt.Printf("Failed to mark flag required: %v\n", err)
+	}
+	err = renameCmd.MarkFlagRequired("to")
+	if err != nil {
+		fmt.Printf("Failed to mark flag required: %v\n", err)
+	}
+
+	return renameCmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewRenameCmd())
+}
@@ -22,17 +22,38 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tnagatomi/gh-fuda/executor"
+	"github.com/tnagatomi/gh-fuda/option"
 )
 
 var (
-	repos    string
-	dryRun   bool
-	labels   string
-	jsonPath string
-	yamlPath string
+	hostname   string
+	repos      string
+	reposFile  string
+	excludes   string
+	owners     []string
+	repoQuery  string
+	repoFilter option.RepoFilter
+	dryRun     bool
+	batchSize  int
+	labels     string
+	jsonPath   string
+	yamlPath   string
+)
+
+// concurrency, ownerConcurrency and maxMutationsPerMinute configure how fast
+// repositories are operated on
+var (
+	concurrency           int
+	ownerConcurrency      int
+	maxMutationsPerMinute int
 )
 
 // version is set via -ldflags during release builds.
@@ -49,16 +70,53 @@ var rootCmd = &cobra.Command{
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+//
+// The first Ctrl-C cancels the context of the command: repositories in
+// progress finish their current label, no other repository is started, and
+// what was done is reported. A second Ctrl-C quits at once.
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		<-interrupts
+		signal.Stop(interrupts)
+		_, _ = fmt.Fprintln(os.Stderr, "\nInterrupted, finishing the repositories in progress (press Ctrl-C again to quit at once)")
+		cancel()
+	}()
+
+	cobra.CheckErr(rootCmd.ExecuteContext(ctx))
+}
+
+// normalizeFlagName makes --owner an alias of --org. Both add to the same
+// list of owners, which two flags bound to one slice would overwrite.
+func normalizeFlagName(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	if name == "owner" {
+		name = "org"
+	}
+	return pflag.NormalizedName(name)
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&repos, "repos", "R", "", "Select repositories using the OWNER/REPO format separated by comma (e.g., owner1/repo1,owner2/repo2)")
+	rootCmd.SetGlobalNormalizationFunc(normalizeFlagName)
+	rootCmd.PersistentFlags().StringVar(&hostname, "hostname", "", "The GitHub host of repositories given without one, such as a GitHub Enterprise Server hostname (default: the default host of gh)")
+	rootCmd.PersistentFlags().StringVarP(&repos, "repos", "R", "", "Select repositories using the OWNER/REPO format separated by comma (e.g., owner1/repo1,owner2/repo2), or OWNER/* for every repository of an owner (\"-\" reads them from stdin like --repos-file)")
+	rootCmd.PersistentFlags().StringVar(&reposFile, "repos-file", "", "Select repositories listed in a file, one OWNER/REPO per line, with # comments and !OWNER/REPO exclusions (\"-\" for stdin)")
+	rootCmd.PersistentFlags().StringVar(&excludes, "exclude-repos", "", "Leave out repositories matching OWNER/REPO glob patterns separated by comma (e.g., 'acme/legacy-*,acme/sandbox'), even when selected otherwise")
+	rootCmd.PersistentFlags().StringSliceVar(&owners, "org", nil, "Select every repository of the specified organizations or users, separated by comma (same as OWNER/* in --repos; alias: --owner)")
+	rootCmd.PersistentFlags().StringVar(&repoQuery, "repo-query", "", "Select the repositories matching a GitHub repository search query (e.g., 'org:acme topic:backend') on the host of --hostname")
+	rootCmd.PersistentFlags().StringSliceVar(&repoFilter.Topics, "topic", nil, "Only select repositories of an owner or the search query with all of these topics, found through repository search")
+	rootCmd.PersistentFlags().StringVar(&repoFilter.Language, "language", "", "Only select repositories of an owner or the search query with this primary language, found through repository search")
+	rootCmd.PersistentFlags().BoolVar(&repoFilter.SkipArchived, "skip-archived", true, "Skip archived repositories when selecting every repository of an owner")
+	rootCmd.PersistentFlags().BoolVar(&repoFilter.SkipForks, "skip-forks", false, "Skip forks when selecting every repository of an owner")
+	rootCmd.PersistentFlags().BoolVar(&repoFilter.SkipTemplates, "skip-templates", false, "Skip template repositories when selecting every repository of an owner")
+	rootCmd.PersistentFlags().BoolVar(&repoFilter.SkipIssuesDisabled, "skip-issues-disabled", false, "Skip repositories without issues enabled when selecting every repository of an owner")
+	rootCmd.PersistentFlags().StringSliceVar(&repoFilter.SkipVisibilities, "skip-visibility", nil, "Skip repositories with these visibilities (public, private, internal; case-insensitive) when selecting every repository of an owner")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Dry run")
-
-	err := rootCmd.MarkPersistentFlagRequired("repos")
-	if err != nil {
-		fmt.Printf("Failed to mark flag required: %v\n", err)
-	}
+	rootCmd.PersistentFlags().IntVar(&batchSize, "batch-size", executor.DefaultBatchSize, "Maximum number of label changes sent to GitHub in one request, and of repositories whose labels are listed in one request")
+	rootCmd.PersistentFlags().IntVar(&concurrency, "concurrency", executor.WorkerPoolSize, "Number of repositories operated on at once")
+	rootCmd.PersistentFlags().IntVar(&ownerConcurrency, "owner-concurrency", 0, "Maximum number of repositories of one owner operated on at once (0 for no limit)")
+	rootCmd.PersistentFlags().IntVar(&maxMutationsPerMinute, "max-mutations-per-minute", 0, "Maximum number of label changes and relabeled items sent to GitHub a minute across every repository (0 for no limit)")
 }
@@ -25,6 +25,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestRootCmd_VersionFlag(t *testing.T) {
@@ -66,3 +68,18 @@ func TestRootCmd_VersionSubcommand(t *testing.T) {
 		t.Errorf("output = %q, want %q", got, want)
 	}
 }
+
+func TestRootCmd_OrgAndOwner(t *testing.T) {
+	defer func() { owners = nil }()
+
+	// --owner is an alias of --org, and both add to the owners
+	flags := rootCmd.PersistentFlags()
+	if err := flags.Parse([]string{"--org", "org1", "--owner", "user1,user2", "--org", "org2"}); err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	want := []string{"org1", "user1", "user2", "org2"}
+	if diff := cmp.Diff(want, owners); diff != "" {
+		t.Errorf("owners mismatch (-want +got):\n%s", diff)
+	}
+}
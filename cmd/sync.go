@@ -22,10 +22,13 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/spf13/cobra"
 	"github.com/tnagatomi/gh-fuda/executor"
+	"github.com/tnagatomi/gh-fuda/option"
 	"github.com/tnagatomi/gh-fuda/parser"
 )
 
@@ -33,6 +36,11 @@ import (
 func NewSyncCmd() *cobra.Command {
 	var skipConfirm bool
 	var forceDeprecated bool
+	var planOut string
+	var deleteUsed bool
+	var mode string
+	var group string
+	var scopePattern string
 
 	var syncCmd = &cobra.Command{
 		Use:   "sync",
@@ -43,16 +51,41 @@ func NewSyncCmd() *cobra.Command {
 				return err
 			}
 
-			repoList, err := parser.Repo(repos)
+			repoList, repoExcludes, err := parseReposInput(cmd.InOrStdin(), repos)
 			if err != nil {
-				return fmt.Errorf("failed to parse repos option: %v", err)
+				return err
+			}
+
+			syncMode, err := executor.ParseSyncMode(mode)
+			if err != nil {
+				return err
+			}
+
+			var scope option.LabelScope
+			switch {
+			case group != "" && scopePattern != "":
+				return errors.New("--group and --scope cannot be used together")
+			case group != "":
+				prefix := option.GroupPrefix(labelList, group)
+				scope = option.PrefixScope(prefix)
+				if !slices.ContainsFunc(labelList, scope.Match) {
+					return fmt.Errorf("no labels in group %q (labels starting with %q)", group, prefix)
+				}
+			case scopePattern != "":
+				scope, err = parser.LabelScope(scopePattern)
+				if err != nil {
+					return err
+				}
+				if !slices.ContainsFunc(labelList, scope.Match) {
+					return fmt.Errorf("no labels match scope %q", scopePattern)
+				}
 			}
 
 			in := cmd.InOrStdin()
 			out := cmd.OutOrStdout()
 
-			if !dryRun && !skipConfirm && !forceDeprecated {
-				confirmed, err := confirm(in, out)
+			if !dryRun && planOut == "" && !skipConfirm && !forceDeprecated {
+				confirmed, err := confirm(cmd.Context(), in, out)
 				if err != nil {
 					return fmt.Errorf("failed to confirm execution: %v", err)
 				}
@@ -62,12 +95,14 @@ func NewSyncCmd() *cobra.Command {
 				}
 			}
 
-			e, err := executor.NewExecutor(dryRun)
+			e, err := newExecutor(dryRun, repoExcludes)
 			if err != nil {
 				return fmt.Errorf("failed to create executor: %v", err)
 			}
+			e.SetPlanPath(planOut)
+			e.SetSyncScope(scope)
 
-			err = e.Sync(out, repoList, labelList)
+			err = e.Sync(cmd.Context(), out, repoList, labelList, syncMode, deleteUsed)
 			if err != nil {
 				return fmt.Errorf("failed to sync labels: %v", err)
 			}
@@ -80,6 +115,12 @@ func NewSyncCmd() *cobra.Command {
 	syncCmd.Flags().BoolVar(&forceDeprecated, "force", false, "Do not prompt for confirmation")
 	_ = syncCmd.Flags().MarkDeprecated("force", "use -y/--yes instead")
 
+	syncCmd.Flags().StringVar(&mode, "mode", string(executor.SyncModeFull), "Operations to make: full (create, update and delete), additive (create and update), update-only (update existing labels), or prune (delete labels not in the set)")
+	syncCmd.Flags().StringVar(&group, "group", "", "Only sync the labels of this label group (e.g., area for area/api, area/ui), leaving the other labels of the repositories alone")
+	syncCmd.Flags().StringVar(&scopePattern, "scope", "", "Only sync the labels whose name matches this glob, where * does not match '/' (e.g., 'team-a/*') or regular expression between slashes (e.g., '/^team-a\\//'), leaving the other labels of the repositories alone")
+	syncCmd.Flags().BoolVar(&deleteUsed, "delete-used", false, "Delete labels that are not in the specified set even if they are still attached to issues, pull requests or discussions")
+	syncCmd.Flags().StringVar(&planOut, "out", "", "Save the plan to the specified file instead of applying it (run it later with the apply command)")
+
 	return syncCmd
 }
 
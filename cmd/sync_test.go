@@ -43,6 +43,36 @@ func TestSyncCmd_MutuallyExclusiveFlags(t *testing.T) {
 			wantErr:     true,
 			errContains: "one of --labels (-l), --json, or --yaml must be specified",
 		},
+		{
+			name:        "invalid mode",
+			args:        []string{"sync", "--labels", "bug:ff0000", "-R", "owner/repo", "--mode", "everything", "-y"},
+			wantErr:     true,
+			errContains: `invalid sync mode "everything"`,
+		},
+		{
+			name:        "group without labels",
+			args:        []string{"sync", "--labels", "bug:ff0000", "-R", "owner/repo", "--mode", "full", "--group", "area", "-y"},
+			wantErr:     true,
+			errContains: `no labels in group "area"`,
+		},
+		{
+			name:        "group and scope together",
+			args:        []string{"sync", "--labels", "bug:ff0000", "-R", "owner/repo", "--group", "area", "--scope", "area/*", "-y"},
+			wantErr:     true,
+			errContains: "--group and --scope cannot be used together",
+		},
+		{
+			name:        "scope without labels",
+			args:        []string{"sync", "--labels", "bug:ff0000", "-R", "owner/repo", "--group=", "--scope", "team-a/*", "-y"},
+			wantErr:     true,
+			errContains: `no labels match scope "team-a/*"`,
+		},
+		{
+			name:        "invalid scope",
+			args:        []string{"sync", "--labels", "bug:ff0000", "-R", "owner/repo", "--group=", "--scope", "/team-(a/", "-y"},
+			wantErr:     true,
+			errContains: "invalid label scope",
+		},
 	}
 
 	for _, tt := range tests {
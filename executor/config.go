@@ -0,0 +1,140 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/tnagatomi/gh-fuda/option"
+	"github.com/tnagatomi/gh-fuda/parser"
+)
+
+// ConfigSyncMode returns the sync mode of cfg, SyncModeFull if it sets none
+func ConfigSyncMode(cfg *option.Config) (SyncMode, error) {
+	if cfg.Mode == "" {
+		return SyncModeFull, nil
+	}
+	return ParseSyncMode(cfg.Mode)
+}
+
+// ApplyConfig syncs every repository selected by the targets of cfg with the
+// labels the targets give it, in mode, as returned by ConfigSyncMode. All
+// repositories are planned and applied together, as with Sync.
+func (e *Executor) ApplyConfig(ctx context.Context, out io.Writer, cfg *option.Config, mode SyncMode) error {
+	e.repoExcludes = append(slices.Clip(e.repoExcludes), e.localPatterns(cfg.Exclude)...)
+
+	repos, repoLabels, err := e.resolveConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	plan, err := e.buildPlan(ctx, out, CommandSync, repos, func(ctx context.Context, repo option.Repo) *RepoPlan {
+		return e.planSync(ctx, repo, repoLabels[repo], mode, cfg.DeleteUsed)
+	})
+	if err != nil {
+		return err
+	}
+	return e.run(ctx, out, plan)
+}
+
+// resolveConfig returns the repositories selected by the targets of cfg, in
+// the order they are first selected, and the labels each one gets. The label
+// sets of a target are added in order, a later label replacing an earlier one
+// with the same name, then the labels of the target override them.
+func (e *Executor) resolveConfig(ctx context.Context, cfg *option.Config) ([]option.Repo, map[option.Repo][]option.Label, error) {
+	var repos []option.Repo
+	repoLabels := make(map[option.Repo][]option.Label)
+	// The repository first selected under each name, ignoring case
+	selected := make(map[string]option.Repo)
+
+	for i, target := range cfg.Targets {
+		filter := e.repoFilter
+		filter.Topics = append(slices.Clip(filter.Topics), target.Topics...)
+		if target.Language != "" {
+			filter.Language = target.Language
+		}
+
+		expanded, err := e.expandRepos(ctx, target.Repos, target.Query, filter)
+		if err != nil {
+			return nil, nil, fmt.Errorf("target at index %d: %v", i, err)
+		}
+
+		for _, repo := range expanded {
+			key := strings.ToLower(repo.String())
+			if first, ok := selected[key]; ok {
+				repo = first
+			} else {
+				selected[key] = repo
+				repos = append(repos, repo)
+			}
+			labels := repoLabels[repo]
+			for _, set := range target.Sets {
+				for _, label := range cfg.LabelSets[set] {
+					labels = setLabel(labels, label)
+				}
+			}
+			for _, label := range target.Labels {
+				labels = overrideLabel(labels, label)
+			}
+			repoLabels[repo] = labels
+		}
+	}
+
+	return repos, repoLabels, nil
+}
+
+// setLabel adds label to labels, replacing a label with the same name
+func setLabel(labels []option.Label, label option.Label) []option.Label {
+	if i := indexOfLabel(label.Name, labels); i >= 0 {
+		labels[i] = label
+		return labels
+	}
+	return append(labels, label)
+}
+
+// overrideLabel sets the non-empty fields of label on the label with the same
+// name, or adds it with a generated color if it has none
+func overrideLabel(labels []option.Label, label option.Label) []option.Label {
+	i := indexOfLabel(label.Name, labels)
+	if i < 0 {
+		if label.Color == "" {
+			label.Color = parser.GenerateColor(label.Name)
+		}
+		return append(labels, label)
+	}
+
+	merged := labels[i]
+	merged.Name = label.Name
+	if label.Color != "" {
+		merged.Color = label.Color
+	}
+	if label.Description != "" {
+		merged.Description = label.Description
+	}
+	merged.Aliases = append(slices.Clip(merged.Aliases), label.Aliases...)
+	labels[i] = merged
+	return labels
+}
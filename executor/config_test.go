@@ -0,0 +1,151 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tnagatomi/gh-fuda/internal/mock"
+	"github.com/tnagatomi/gh-fuda/option"
+	"github.com/tnagatomi/gh-fuda/parser"
+)
+
+func TestResolveConfig(t *testing.T) {
+	bug := option.Label{Name: "bug", Color: "d73a4a", Description: "Something isn't working"}
+	question := option.Label{Name: "question", Color: "d876e3"}
+	ui := option.Label{Name: "ui", Color: "0e8a16"}
+
+	cfg := &option.Config{
+		LabelSets: map[string][]option.Label{
+			"common":   {bug, question},
+			"frontend": {ui, {Name: "Question", Color: "cccccc"}},
+		},
+		Targets: []option.ConfigTarget{
+			{Repos: []option.Repo{{Owner: "acme", Repo: "*"}}, Sets: []string{"common"}},
+			{Repos: []option.Repo{{Owner: "acme", Repo: "*"}}, Topics: []string{"frontend"}, Sets: []string{"frontend"}},
+			{
+				Repos: []option.Repo{{Owner: "ACME", Repo: "Web"}, {Owner: "other", Repo: "site"}},
+				Labels: []option.Label{
+					{Name: "bug", Color: "ff0000"},
+					{Name: "design", Description: "Design work"},
+				},
+			},
+		},
+	}
+
+	m := &mock.MockAPI{
+		ListRepositoriesFunc: func(owner string) ([]option.Repository, error) {
+			return []option.Repository{
+				{Repo: option.Repo{Owner: "acme", Repo: "api"}},
+				{Repo: option.Repo{Owner: "acme", Repo: "web"}, Topics: []string{"frontend"}},
+			}, nil
+		},
+		SearchRepositoriesFunc: func(query string) ([]option.Repository, error) {
+			if query != "user:acme topic:frontend fork:true" {
+				t.Errorf("resolveConfig() search query = %q", query)
+			}
+			return []option.Repository{
+				{Repo: option.Repo{Owner: "acme", Repo: "web"}, Topics: []string{"frontend"}},
+			}, nil
+		},
+	}
+	e := &Executor{api: m}
+
+	repos, repoLabels, err := e.resolveConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("resolveConfig() error = %v", err)
+	}
+
+	wantRepos := []option.Repo{
+		{Owner: "acme", Repo: "api"},
+		{Owner: "acme", Repo: "web"},
+		{Owner: "other", Repo: "site"},
+	}
+	if diff := cmp.Diff(wantRepos, repos); diff != "" {
+		t.Errorf("resolveConfig() repos mismatch (-want +got):\n%s", diff)
+	}
+
+	design := option.Label{Name: "design", Color: parser.GenerateColor("design"), Description: "Design work"}
+	wantLabels := map[option.Repo][]option.Label{
+		{Owner: "acme", Repo: "api"}: {bug, question},
+		{Owner: "acme", Repo: "web"}: {
+			{Name: "bug", Color: "ff0000", Description: "Something isn't working"},
+			{Name: "Question", Color: "cccccc"},
+			ui,
+			design,
+		},
+		{Owner: "other", Repo: "site"}: {{Name: "bug", Color: "ff0000"}, design},
+	}
+	if diff := cmp.Diff(wantLabels, repoLabels); diff != "" {
+		t.Errorf("resolveConfig() labels mismatch (-want +got):\n%s", diff)
+	}
+
+	// The sets themselves are left as they are
+	if diff := cmp.Diff([]option.Label{bug, question}, cfg.LabelSets["common"]); diff != "" {
+		t.Errorf("resolveConfig() modified a label set (-want +got):\n%s", diff)
+	}
+}
+
+func TestApplyConfig_DryRun(t *testing.T) {
+	cfg := &option.Config{
+		Mode:    "additive",
+		Exclude: option.RepoPatterns{"acme/legacy-*"},
+		LabelSets: map[string][]option.Label{
+			"common": {{Name: "bug", Color: "d73a4a"}},
+		},
+		Targets: []option.ConfigTarget{
+			{Repos: []option.Repo{{Owner: "acme", Repo: "*"}}, Sets: []string{"common"}},
+			{Repos: []option.Repo{{Owner: "acme", Repo: "web"}}, Labels: []option.Label{{Name: "ui", Color: "0e8a16"}}},
+		},
+	}
+
+	m := &mock.MockAPI{
+		ListRepositoriesFunc: func(owner string) ([]option.Repository, error) {
+			return []option.Repository{
+				{Repo: option.Repo{Owner: "acme", Repo: "api"}},
+				{Repo: option.Repo{Owner: "acme", Repo: "legacy-app"}},
+				{Repo: option.Repo{Owner: "acme", Repo: "web"}},
+			}, nil
+		},
+		ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+			return []option.Label{{Name: "wontfix", Color: "ffffff"}}, nil
+		},
+	}
+	e := &Executor{api: m, dryRun: true}
+
+	out := &bytes.Buffer{}
+	if err := e.ApplyConfig(context.Background(), out, cfg, SyncModeAdditive); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+
+	want := `Would skip repository "acme/legacy-app": excluded
+Would create label "bug" for repository "acme/api"
+Would create label "bug" for repository "acme/web"
+Would create label "ui" for repository "acme/web"
+`
+	if diff := cmp.Diff(want, stripProgress(out.String())); diff != "" {
+		t.Errorf("ApplyConfig() output mismatch (-want +got):\n%s", diff)
+	}
+}
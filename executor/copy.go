@@ -0,0 +1,91 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+// CopyOptions controls how labels are copied from a source repository
+type CopyOptions struct {
+	// Include limits the copied labels to those matching any of these
+	// patterns. Empty copies every label.
+	Include []string
+	// Exclude skips labels matching any of these patterns
+	Exclude []string
+	// Sync makes the targets match the copied labels, deleting the others
+	Sync bool
+	// Force updates labels that already exist in a target when not syncing
+	Force bool
+}
+
+// Copy copies the labels of source to the repositories, with the create or,
+// if opts.Sync is set, the sync flow. Patterns are globs matched against label
+// names with option.MatchGlob, and also scope the sync, so that only labels
+// matching them are deleted. The source itself is never a target.
+func (e *Executor) Copy(ctx context.Context, out io.Writer, source option.Repo, repos []option.Repo, opts CopyOptions) error {
+	for _, pattern := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if err := option.CheckGlob(pattern); err != nil {
+			return fmt.Errorf("invalid label pattern %q: %v", pattern, err)
+		}
+	}
+
+	source = e.localRepo(source)
+	if err := e.connect(source); err != nil {
+		return err
+	}
+
+	sourceLabels, err := e.apiFor(source).ListLabels(ctx, source)
+	if err != nil {
+		return fmt.Errorf("failed to list labels for source repository %q: %v", source, err)
+	}
+
+	scope := option.LabelScope{Include: opts.Include, Exclude: opts.Exclude}
+	var labels []option.Label
+	for _, label := range sourceLabels {
+		if scope.Match(label) {
+			labels = append(labels, label)
+		}
+	}
+
+	if opts.Sync {
+		if err := option.CheckSyncLabels(labels); err != nil {
+			return fmt.Errorf("no labels of source repository %q are selected: %w", source, err)
+		}
+	}
+
+	// The source is never a target, even when a wildcard selects it
+	e.repoExcludes = append(slices.Clip(e.repoExcludes), source.String())
+
+	_, _ = fmt.Fprintf(out, "Copying %d of %d labels from repository %q\n", len(labels), len(sourceLabels), source)
+
+	if opts.Sync {
+		e.SetSyncScope(scope)
+		return e.Sync(ctx, out, repos, labels, SyncModeFull, false)
+	}
+	return e.Create(ctx, out, repos, labels, opts.Force)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tnagatomi/gh-fuda/internal/mock"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+func TestCopy(t *testing.T) {
+	source := option.Repo{Owner: "owner", Repo: "template"}
+	target := option.Repo{Owner: "owner", Repo: "target"}
+	sourceLabels := []option.Label{
+		{Name: "area/api", Color: "0000ff"},
+		{Name: "area/cli", Color: "00ff00"},
+		{Name: "Bug", Color: "d73a4a"},
+		{Name: "wip", Color: "ffffff"},
+	}
+
+	tests := []struct {
+		name       string
+		repos      []option.Repo
+		opts       CopyOptions
+		wantOut    string
+		wantErr    bool
+		wantCreate []string
+		wantDelete []string
+	}{
+		{
+			name:  "copy all labels to targets other than the source",
+			repos: []option.Repo{source, target},
+			opts:  CopyOptions{},
+			wantOut: `Copying 4 of 4 labels from repository "owner/template"
+Created label "area/api" for repository "owner/target"
+Created label "area/cli" for repository "owner/target"
+Created label "Bug" for repository "owner/target"
+Created label "wip" for repository "owner/target"
+
+Summary: all operations completed successfully
+  owner/target: 4 created, 0 updated, 0 deleted, 0 unchanged
+  owner/template: excluded
+`,
+			wantCreate: []string{"area/api", "area/cli", "Bug", "wip"},
+		},
+		{
+			name:  "include and exclude patterns",
+			repos: []option.Repo{target},
+			opts:  CopyOptions{Include: []string{"area/*", "bug"}, Exclude: []string{"*/CLI"}},
+			wantOut: `Copying 2 of 4 labels from repository "owner/template"
+Created label "area/api" for repository "owner/target"
+Created label "Bug" for repository "owner/target"
+
+Summary: all operations completed successfully
+  owner/target: 2 created, 0 updated, 0 deleted, 0 unchanged
+`,
+			wantCreate: []string{"area/api", "Bug"},
+		},
+		{
+			name:  "sync",
+			repos: []option.Repo{target},
+			opts:  CopyOptions{Sync: true},
+			wantOut: `Copying 4 of 4 labels from repository "owner/template"
+Deleted label "question" for repository "owner/target"
+Deleted label "area/legacy" for repository "owner/target"
+Deleted label "area/tools" for repository "owner/target"
+Created label "area/api" for repository "owner/target"
+Created label "area/cli" for repository "owner/target"
+Created label "Bug" for repository "owner/target"
+Created label "wip" for repository "owner/target"
+
+Summary: all operations completed successfully
+  owner/target: 4 created, 0 updated, 3 deleted, 0 unchanged
+`,
+			wantCreate: []string{"area/api", "area/cli", "Bug", "wip"},
+			wantDelete: []string{"question", "area/legacy", "area/tools"},
+		},
+		{
+			name:  "sync only deletes labels matching the patterns",
+			repos: []option.Repo{target},
+			opts:  CopyOptions{Include: []string{"area/*"}, Exclude: []string{"*/tools"}, Sync: true},
+			wantOut: `Copying 2 of 4 labels from repository "owner/template"
+Deleted label "area/legacy" for repository "owner/target"
+Created label "area/api" for repository "owner/target"
+Created label "area/cli" for repository "owner/target"
+
+Summary: all operations completed successfully
+  owner/target: 2 created, 0 updated, 1 deleted, 0 unchanged
+`,
+			wantCreate: []string{"area/api", "area/cli"},
+			wantDelete: []string{"area/legacy"},
+		},
+		{
+			name:    "sync without selected labels",
+			repos:   []option.Repo{target},
+			opts:    CopyOptions{Include: []string{"nothing-*"}, Sync: true},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			repos:   []option.Repo{target},
+			opts:    CopyOptions{Include: []string{"["}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					if repo == source {
+						return sourceLabels, nil
+					}
+					return []option.Label{{Name: "question"}, {Name: "area/legacy"}, {Name: "area/tools"}}, nil
+				},
+			}
+			e := &Executor{api: m}
+			out := &bytes.Buffer{}
+
+			err := e.Copy(context.Background(), out, source, tt.repos, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Copy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantOut, stripProgress(out.String())); diff != "" {
+				t.Errorf("Copy() output mismatch (-want +got):\n%s", diff)
+			}
+
+			var created, deleted []string
+			for _, call := range m.CreateLabelCalls {
+				if call.Repo != target {
+					t.Errorf("Copy() created label %q in %q", call.Label.Name, call.Repo)
+				}
+				created = append(created, call.Label.Name)
+			}
+			for _, call := range m.DeleteLabelCalls {
+				deleted = append(deleted, call.Label)
+			}
+			if diff := cmp.Diff(tt.wantCreate, created); diff != "" {
+				t.Errorf("Copy() created labels mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDelete, deleted); diff != "" {
+				t.Errorf("Copy() deleted labels mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCopy_SourceError(t *testing.T) {
+	m := &mock.MockAPI{
+		ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+			return nil, errors.New("boom")
+		},
+	}
+	e := &Executor{api: m}
+
+	err := e.Copy(context.Background(), &bytes.Buffer{}, option.Repo{Owner: "owner", Repo: "template"}, []option.Repo{{Owner: "owner", Repo: "target"}}, CopyOptions{})
+	if err == nil || err.Error() != `failed to list labels for source repository "owner/template": boom` {
+		t.Errorf("Copy() error = %v", err)
+	}
+	if len(m.CreateLabelCalls) > 0 {
+		t.Errorf("Copy() created labels after failing to list the source")
+	}
+}
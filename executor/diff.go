@@ -0,0 +1,163 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+// Diff reports, for each repository, the labels that are missing, extra or
+// different compared to labels. Nothing is changed. It returns an error if
+// any repository could not be checked or its labels differ.
+func (e *Executor) Diff(ctx context.Context, out io.Writer, repos []option.Repo, labels []option.Label) error {
+	repos, excluded, err := e.resolveRepos(ctx, repos)
+	if err != nil {
+		return err
+	}
+	printExcluded(out, excluded)
+
+	e.prefetchLabels(ctx, repos)
+	defer e.clearPrefetched()
+
+	wp := e.newWorkerPool(out)
+	jobs := make([]Job, len(repos))
+	drifted := make([]bool, len(repos))
+
+	for i, repo := range repos {
+		jobs[i] = Job{
+			ID:  i,
+			Key: e.ownerKey(repo),
+			Func: func(ctx context.Context) *JobResult {
+				result, drift := e.diffLabelsForRepo(ctx, repo, labels)
+				drifted[i] = drift
+				return result
+			},
+		}
+	}
+
+	results := wp.Run(ctx, jobs)
+	wp.ClearProgress()
+
+	// Output all results together
+	er := NewExecutionResult()
+	for i, result := range results {
+		_, _ = fmt.Fprint(out, result.Output)
+		er.AddJobResult(repos[i].String(), result)
+	}
+
+	driftCount := 0
+	for _, drift := range drifted {
+		if drift {
+			driftCount++
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "\n%s\n", er.Summary())
+	_, _ = fmt.Fprint(out, er.NotStartedSummary())
+	if err := er.Err(); err != nil {
+		return err
+	}
+	if driftCount > 0 {
+		return fmt.Errorf("labels differ in %d of %d repositories", driftCount, len(repos))
+	}
+	return nil
+}
+
+// diffLabelsForRepo compares the labels of a repository with labels and
+// returns whether they differ
+func (e *Executor) diffLabelsForRepo(ctx context.Context, repo option.Repo, labels []option.Label) (*JobResult, bool) {
+	var output strings.Builder
+
+	existingLabels, err := e.listLabels(ctx, repo)
+	if err != nil {
+		fmt.Fprintf(&output, "Failed to list labels for repository %q: %v\n", repo, err)
+		return &JobResult{
+			Output:  output.String(),
+			Success: false,
+			Errors:  []error{err},
+		}, false
+	}
+
+	var lines []string
+	matches := matchExisting(labels, existingLabels)
+
+	for i, label := range labels {
+		j := matches[i]
+		if j < 0 {
+			lines = append(lines, fmt.Sprintf("  missing: %s", describeLabel(label)))
+			continue
+		}
+		if changes := labelChanges(existingLabels[j], label); changes != "" {
+			lines = append(lines, fmt.Sprintf("  differs: %q %s", existingLabels[j].Name, changes))
+		}
+	}
+
+	for i, existing := range existingLabels {
+		if !matched(i, matches) {
+			lines = append(lines, fmt.Sprintf("  extra: %s", describeLabel(existing)))
+		}
+	}
+
+	if len(lines) == 0 {
+		fmt.Fprintf(&output, "Labels for repository %q are in sync\n", repo)
+	} else {
+		fmt.Fprintf(&output, "Labels for repository %q differ:\n", repo)
+		for _, line := range lines {
+			fmt.Fprintln(&output, line)
+		}
+	}
+
+	return &JobResult{
+		Output:  output.String(),
+		Success: true,
+	}, len(lines) > 0
+}
+
+// describeLabel formats a label with its color and description
+func describeLabel(label option.Label) string {
+	if label.Description == "" {
+		return fmt.Sprintf("%q (#%s)", label.Name, label.Color)
+	}
+	return fmt.Sprintf("%q (#%s) - %s", label.Name, label.Color, label.Description)
+}
+
+// labelChanges describes how existing differs from label, with the current
+// value on the left and the desired one on the right, or returns "" if they
+// are the same
+func labelChanges(existing, label option.Label) string {
+	var changes []string
+	if existing.Name != label.Name {
+		changes = append(changes, fmt.Sprintf("name %q -> %q", existing.Name, label.Name))
+	}
+	if !sameColor(existing.Color, label.Color) {
+		changes = append(changes, fmt.Sprintf("color #%s -> #%s", existing.Color, label.Color))
+	}
+	if !sameDescription(existing.Description, label.Description) {
+		changes = append(changes, fmt.Sprintf("description %q -> %q", existing.Description, label.Description))
+	}
+	return strings.Join(changes, ", ")
+}
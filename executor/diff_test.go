@@ -0,0 +1,154 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tnagatomi/gh-fuda/internal/mock"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+func TestDiff(t *testing.T) {
+	labels := []option.Label{
+		{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+		{Name: "kind: feature", Color: "a2eeef", Aliases: []string{"enhancement"}},
+		{Name: "documentation", Color: "0075ca"},
+	}
+
+	tests := []struct {
+		name     string
+		repos    []option.Repo
+		existing map[string][]option.Label
+		listErr  error
+		wantOut  string
+		wantErr  string
+	}{
+		{
+			name:  "in sync",
+			repos: []option.Repo{{Owner: "owner", Repo: "repo"}},
+			existing: map[string][]option.Label{
+				"repo": {
+					{Name: "bug", Color: "D73A4A", Description: "Something isn't working"},
+					{Name: "kind: feature", Color: "a2eeef"},
+					{Name: "documentation", Color: "0075ca", Description: "Improvements or additions to documentation"},
+				},
+			},
+			wantOut: `Labels for repository "owner/repo" are in sync
+
+Summary: all operations completed successfully
+`,
+		},
+		{
+			name:  "missing, extra and differing labels",
+			repos: []option.Repo{{Owner: "owner", Repo: "repo-1"}, {Owner: "owner", Repo: "repo-2"}},
+			existing: map[string][]option.Label{
+				"repo-1": {
+					{Name: "bug", Color: "ff0000", Description: "A bug"},
+					{Name: "enhancement", Color: "a2eeef"},
+					{Name: "question", Color: "d876e3"},
+				},
+				"repo-2": {
+					{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+					{Name: "kind: feature", Color: "a2eeef"},
+					{Name: "documentation", Color: "0075ca"},
+				},
+			},
+			wantOut: `Labels for repository "owner/repo-1" differ:
+  differs: "bug" color #ff0000 -> #d73a4a, description "A bug" -> "Something isn't working"
+  differs: "enhancement" name "enhancement" -> "kind: feature"
+  missing: "documentation" (#0075ca)
+  extra: "question" (#d876e3)
+Labels for repository "owner/repo-2" are in sync
+
+Summary: all operations completed successfully
+`,
+			wantErr: "labels differ in 1 of 2 repositories",
+		},
+		{
+			name:    "list error",
+			repos:   []option.Repo{{Owner: "owner", Repo: "repo"}},
+			listErr: errors.New("boom"),
+			wantOut: `Failed to list labels for repository "owner/repo": boom
+
+Summary: 0 repositories succeeded, 1 failed
+`,
+			wantErr: "some operations failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					if tt.listErr != nil {
+						return nil, tt.listErr
+					}
+					return tt.existing[repo.Repo], nil
+				},
+			}
+			e := &Executor{api: m}
+			out := &bytes.Buffer{}
+
+			err := e.Diff(context.Background(), out, tt.repos, labels)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("Diff() error = %v, wantErr %q", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantOut, stripProgress(out.String())); diff != "" {
+				t.Errorf("Diff() output mismatch (-want +got):\n%s", diff)
+			}
+			if len(m.CreateLabelCalls)+len(m.UpdateLabelCalls)+len(m.DeleteLabelCalls) > 0 {
+				t.Errorf("Diff() changed labels")
+			}
+		})
+	}
+}
+
+func TestDiff_Excluded(t *testing.T) {
+	labels := []option.Label{{Name: "bug", Color: "d73a4a"}}
+	m := &mock.MockAPI{
+		ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+			return labels, nil
+		},
+	}
+	e := &Executor{api: m}
+	e.SetRepoExcludes(option.RepoPatterns{"owner/legacy-*"})
+
+	out := &bytes.Buffer{}
+	repos := []option.Repo{{Owner: "owner", Repo: "repo"}, {Owner: "owner", Repo: "legacy-app"}}
+	if err := e.Diff(context.Background(), out, repos, labels); err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	want := `Skipped repository "owner/legacy-app": excluded
+Labels for repository "owner/repo" are in sync
+
+Summary: all operations completed successfully
+`
+	if diff := cmp.Diff(want, stripProgress(out.String())); diff != "" {
+		t.Errorf("Diff() output mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -22,20 +22,49 @@ THE SOFTWARE.
 package executor
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/tnagatomi/gh-fuda/api"
 )
 
+// ErrInterrupted is returned when the context of an operation is canceled,
+// such as by Ctrl-C, before every repository was operated on
+var ErrInterrupted = errors.New("interrupted")
+
+// OperationCounts counts the labels a repository's operations changed
+type OperationCounts struct {
+	Created   int
+	Updated   int
+	Deleted   int
+	Unchanged int
+}
+
+func (c OperationCounts) String() string {
+	return fmt.Sprintf("%d created, %d updated, %d deleted, %d unchanged", c.Created, c.Updated, c.Deleted, c.Unchanged)
+}
+
 // RepoResult represents the result of operations on a repository
 type RepoResult struct {
 	Repo   string
 	Errors []error
+	Counts OperationCounts
+	// Interrupted is true if the operations on the repository stopped
+	// before all of them were done
+	Interrupted bool
 }
 
 // ExecutionResult collects and reports the results of execution
 type ExecutionResult struct {
 	results map[string]*RepoResult
+	// repos keeps the order in which results were added, for the summary
+	repos []string
+	// excluded lists the repositories left out on purpose
+	excluded []string
+	// notStarted lists the repositories never operated on because the
+	// execution was interrupted
+	notStarted []string
 }
 
 // NewExecutionResult creates a new ExecutionResult
@@ -47,9 +76,52 @@ func NewExecutionResult() *ExecutionResult {
 
 // AddRepoResult adds a repository result to the execution result
 func (er *ExecutionResult) AddRepoResult(result *RepoResult) {
+	if _, ok := er.results[result.Repo]; !ok {
+		er.repos = append(er.repos, result.Repo)
+	}
 	er.results[result.Repo] = result
 }
 
+// AddExcluded records a repository left out by the repository exclusions
+func (er *ExecutionResult) AddExcluded(repo string) {
+	er.excluded = append(er.excluded, repo)
+}
+
+// AddNotStarted records a repository never operated on because the execution
+// was interrupted
+func (er *ExecutionResult) AddNotStarted(repo string) {
+	er.notStarted = append(er.notStarted, repo)
+}
+
+// AddJobResult records the result of the job operating on repo, or that it
+// never started
+func (er *ExecutionResult) AddJobResult(repo string, result *JobResult) {
+	if result.NotStarted {
+		er.AddNotStarted(repo)
+		return
+	}
+	er.AddRepoResult(&RepoResult{
+		Repo:        repo,
+		Errors:      result.Errors,
+		Counts:      result.Counts,
+		Interrupted: result.Interrupted,
+	})
+}
+
+// Interrupted returns true if some repositories were not operated on, or not
+// completely, because the execution was interrupted
+func (er *ExecutionResult) Interrupted() bool {
+	if len(er.notStarted) > 0 {
+		return true
+	}
+	for _, result := range er.results {
+		if result.Interrupted {
+			return true
+		}
+	}
+	return false
+}
+
 // RepoResult returns the result for a specific repository, or nil if not found
 func (er *ExecutionResult) RepoResult(repoName string) *RepoResult {
 	return er.results[repoName]
@@ -74,15 +146,23 @@ func (er *ExecutionResult) IsNotFoundError(err error) bool {
 func (er *ExecutionResult) Summary() string {
 	successCount := 0
 	failCount := 0
+	interruptedCount := 0
 
 	for _, result := range er.results {
 		if len(result.Errors) > 0 {
 			failCount++
+		} else if result.Interrupted {
+			interruptedCount++
 		} else {
 			successCount++
 		}
 	}
 
+	if er.Interrupted() {
+		return fmt.Sprintf("Summary: interrupted, %d repositories succeeded, %d failed, %d stopped partway, %d not started",
+			successCount, failCount, interruptedCount, len(er.notStarted))
+	}
+
 	if failCount == 0 {
 		return "Summary: all operations completed successfully"
 	}
@@ -91,8 +171,46 @@ func (er *ExecutionResult) Summary() string {
 		successCount, failCount)
 }
 
-// Err returns an error if any operations failed
+// CountsSummary returns the operation counts of each repository, one line per
+// repository in the order the results were added, followed by the excluded
+// repositories
+func (er *ExecutionResult) CountsSummary() string {
+	var b strings.Builder
+	for _, repo := range er.repos {
+		result := er.results[repo]
+		if result.Interrupted {
+			fmt.Fprintf(&b, "  %s: %s (interrupted)\n", repo, result.Counts)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", repo, result.Counts)
+	}
+	for _, repo := range er.excluded {
+		fmt.Fprintf(&b, "  %s: excluded\n", repo)
+	}
+	return b.String()
+}
+
+// NotStartedSummary lists the repositories never operated on because the
+// execution was interrupted, one per line, or returns an empty string if
+// there are none
+func (er *ExecutionResult) NotStartedSummary() string {
+	if len(er.notStarted) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Not started:\n")
+	for _, repo := range er.notStarted {
+		fmt.Fprintf(&b, "  %s\n", repo)
+	}
+	return b.String()
+}
+
+// Err returns ErrInterrupted if the execution was interrupted, or an error if
+// any operations failed
 func (er *ExecutionResult) Err() error {
+	if er.Interrupted() {
+		return ErrInterrupted
+	}
 	if !er.HasErrors() {
 		return nil
 	}
@@ -22,617 +22,664 @@ THE SOFTWARE.
 package executor
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
+	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/tnagatomi/gh-fuda/api"
 	"github.com/tnagatomi/gh-fuda/option"
 )
 
+// DefaultBatchSize is the maximum number of label changes sent in one request,
+// and of repositories whose labels are listed in one request, unless set
+// otherwise
+const DefaultBatchSize = 50
+
 // Executor composites github.Client and has dry-run option
 type Executor struct {
-	api    api.APIClient
+	// api is the client of the default host
+	api api.APIClient
+	// host is the default host, of repositories without one
+	host string
+	// hostAPIs holds the clients of the other hosts, created by connect
+	hostAPIs map[string]api.APIClient
+	// newAPI creates the client of a host
+	newAPI func(host string) (api.APIClient, error)
 	dryRun bool
+	// planPath is where the computed plan is saved instead of being applied
+	planPath string
+	// repoFilter selects the repositories wildcards and repoQuery expand to
+	repoFilter option.RepoFilter
+	// repoQuery is a repository search query selecting additional repositories
+	repoQuery string
+	// batchSize is the maximum number of label changes sent in one request,
+	// and of repositories whose labels are listed in one request
+	batchSize int
+	// prefetched holds the labels listed by prefetchLabels
+	prefetched map[option.Repo]prefetchedLabels
+	// syncScope selects the only labels sync manages
+	syncScope option.LabelScope
+	// concurrency is the number of repositories operated on at once, 0 for
+	// WorkerPoolSize
+	concurrency int
+	// ownerConcurrency is the maximum number of repositories of one owner
+	// operated on at once, 0 for no limit
+	ownerConcurrency int
+	// maxMutationsPerMinute is the maximum average number of mutations sent a
+	// minute, 0 for no limit
+	maxMutationsPerMinute int
+	// mutations spaces out the mutations of every worker, nil for no limit
+	mutations *mutationLimiter
+	// repoExcludes matches repositories never operated on, even when named
+	// explicitly or matched by a wildcard
+	repoExcludes option.RepoPatterns
 }
 
-// NewExecutor returns new Executor
-func NewExecutor(dryrun bool) (*Executor, error) {
-	apiClient, err := api.NewGraphQLAPI()
+// NewExecutor returns new Executor. Repositories without a host are on
+// hostname, which defaults to the default host of gh.
+func NewExecutor(dryrun bool, hostname string) (*Executor, error) {
+	if hostname == "" {
+		hostname, _ = auth.DefaultHost()
+	}
+
+	apiClient, err := api.NewGraphQLAPI(hostname)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize API client: %v", err)
 	}
 
 	return &Executor{
-		api:    apiClient,
+		api:  apiClient,
+		host: strings.ToLower(hostname),
+		newAPI: func(host string) (api.APIClient, error) {
+			return api.NewGraphQLAPI(host)
+		},
 		dryRun: dryrun,
 	}, nil
 }
 
 // Create creates labels across multiple repositories
 // If force is true, updates existing labels instead of failing
-func (e *Executor) Create(out io.Writer, repos []option.Repo, labels []option.Label, force bool) error {
-	// Dry-run mode: execute sequentially with immediate output
-	if e.dryRun {
-		return e.createDryRun(out, repos, labels, force)
+func (e *Executor) Create(ctx context.Context, out io.Writer, repos []option.Repo, labels []option.Label, force bool) error {
+	plan, err := e.buildPlan(ctx, out, CommandCreate, repos, func(ctx context.Context, repo option.Repo) *RepoPlan {
+		return e.planCreate(ctx, repo, labels, force)
+	})
+	if err != nil {
+		return err
 	}
-
-	// Normal mode: execute in parallel
-	return e.createParallel(out, repos, labels, force)
+	return e.run(ctx, out, plan)
 }
 
-func (e *Executor) createDryRun(out io.Writer, repos []option.Repo, labels []option.Label, force bool) error {
-	var hasError bool
-	for _, repo := range repos {
-		var existingLabels []option.Label
-		if force {
-			var err error
-			existingLabels, err = e.api.ListLabels(repo)
-			if err != nil {
-				_, _ = fmt.Fprintf(out, "Failed to list labels for repository %q: %v\n", repo, err)
-				hasError = true
-				continue
-			}
-		}
-
-		for _, label := range labels {
-			if force && labelExists(label.Name, existingLabels) {
-				_, _ = fmt.Fprintf(out, "Would update label %q for repository %q\n", label, repo)
-			} else {
-				_, _ = fmt.Fprintf(out, "Would create label %q for repository %q\n", label, repo)
-			}
-		}
-	}
-	if hasError {
-		return fmt.Errorf("some operations failed")
+// Delete deletes labels across multiple repositories
+func (e *Executor) Delete(ctx context.Context, out io.Writer, repos []option.Repo, labels []string) error {
+	plan, err := e.buildPlan(ctx, out, CommandDelete, repos, func(ctx context.Context, repo option.Repo) *RepoPlan {
+		return e.planDelete(ctx, repo, labels)
+	})
+	if err != nil {
+		return err
 	}
-	return nil
+	return e.run(ctx, out, plan)
 }
 
-func (e *Executor) createParallel(out io.Writer, repos []option.Repo, labels []option.Label, force bool) error {
-	wp := NewWorkerPool(out)
-	jobs := make([]Job, len(repos))
-
-	for i, repo := range repos {
-		jobs[i] = Job{
-			ID: i,
-			Func: func() *JobResult {
-				return e.createLabelsForRepo(repo, labels, force)
-			},
-		}
+// Sync sync labels across multiple repositories. The mode selects which
+// operations are made. Labels still attached to issues, pull requests or
+// discussions are kept unless deleteUsed is set.
+func (e *Executor) Sync(ctx context.Context, out io.Writer, repos []option.Repo, labels []option.Label, mode SyncMode, deleteUsed bool) error {
+	plan, err := e.buildPlan(ctx, out, CommandSync, repos, func(ctx context.Context, repo option.Repo) *RepoPlan {
+		return e.planSync(ctx, repo, labels, mode, deleteUsed)
+	})
+	if err != nil {
+		return err
 	}
+	return e.run(ctx, out, plan)
+}
 
-	results := wp.Run(jobs)
-	wp.ClearProgress()
-
-	// Output all results together
-	er := NewExecutionResult()
-	for i, result := range results {
-		_, _ = fmt.Fprint(out, result.Output)
-		er.AddRepoResult(&RepoResult{
-			Repo:   repos[i].String(),
-			Errors: result.Errors,
-		})
+// Empty empties labels across multiple repositories
+func (e *Executor) Empty(ctx context.Context, out io.Writer, repos []option.Repo) error {
+	plan, err := e.buildPlan(ctx, out, CommandEmpty, repos, e.planEmpty)
+	if err != nil {
+		return err
 	}
-
-	_, _ = fmt.Fprintf(out, "\n%s\n", er.Summary())
-	return er.Err()
+	return e.run(ctx, out, plan)
 }
 
-func (e *Executor) createLabelsForRepo(repo option.Repo, labels []option.Label, force bool) *JobResult {
-	var output strings.Builder
-	var errors []error
-
-	for _, label := range labels {
-		err := e.api.CreateLabel(label, repo)
-		if err != nil {
-			// If force flag is set and label already exists, try to update it
-			if force && api.IsAlreadyExists(err) {
-				err = e.api.UpdateLabel(label, repo)
-				if err != nil {
-					fmt.Fprintf(&output, "Failed to update label %q for repository %q: %v\n", label, repo, err)
-					errors = append(errors, err)
-					continue
-				}
-				fmt.Fprintf(&output, "Updated label %q for repository %q\n", label, repo)
-				continue
-			}
-
-			fmt.Fprintf(&output, "Failed to create label %q for repository %q: %v\n", label, repo, err)
-			errors = append(errors, err)
-			continue
-		}
-		fmt.Fprintf(&output, "Created label %q for repository %q\n", label, repo)
+// Merge merges a source label into a target label across multiple repositories.
+// It adds the target label to all items with the source label, removes the source label,
+// and then deletes the source label from the repository.
+func (e *Executor) Merge(ctx context.Context, out io.Writer, repos []option.Repo, fromLabel, toLabel string) error {
+	plan, err := e.buildPlan(ctx, out, CommandMerge, repos, func(ctx context.Context, repo option.Repo) *RepoPlan {
+		return e.planMerge(ctx, repo, fromLabel, toLabel)
+	})
+	if err != nil {
+		return err
 	}
+	return e.run(ctx, out, plan)
+}
 
-	return &JobResult{
-		Output:  output.String(),
-		Success: len(errors) == 0,
-		Errors:  errors,
-	}
+// SetPlanPath makes the executor save computed plans to path instead of applying them
+func (e *Executor) SetPlanPath(path string) {
+	e.planPath = path
 }
 
-// Delete deletes labels across multiple repositories
-func (e *Executor) Delete(out io.Writer, repos []option.Repo, labels []string) error {
-	// Dry-run mode: execute sequentially with immediate output
-	if e.dryRun {
-		return e.deleteDryRun(out, repos, labels)
-	}
+// SetBatchSize sets the maximum number of label changes sent in one request,
+// which is also the maximum number of repositories whose labels are listed in
+// one request
+func (e *Executor) SetBatchSize(size int) {
+	e.batchSize = size
+}
 
-	// Normal mode: execute in parallel
-	return e.deleteParallel(out, repos, labels)
+// SetConcurrency sets the number of repositories operated on at once
+func (e *Executor) SetConcurrency(n int) {
+	e.concurrency = n
 }
 
-func (e *Executor) deleteDryRun(out io.Writer, repos []option.Repo, labels []string) error {
-	for _, repo := range repos {
-		for _, label := range labels {
-			_, _ = fmt.Fprintf(out, "Would delete label %q for repository %q\n", label, repo)
-		}
-	}
-	return nil
+// SetOwnerConcurrency sets the maximum number of repositories of one owner
+// operated on at once, 0 for no limit. 1 serializes the requests to each owner.
+func (e *Executor) SetOwnerConcurrency(n int) {
+	e.ownerConcurrency = n
 }
 
-func (e *Executor) deleteParallel(out io.Writer, repos []option.Repo, labels []string) error {
-	wp := NewWorkerPool(out)
-	jobs := make([]Job, len(repos))
+// SetMaxMutationsPerMinute spaces out the label changes and the relabeling of
+// items of every repository to an average of n a minute, 0 for no limit
+func (e *Executor) SetMaxMutationsPerMinute(n int) {
+	e.maxMutationsPerMinute = n
+	e.mutations = newMutationLimiter(n)
+}
 
-	for i, repo := range repos {
-		jobs[i] = Job{
-			ID: i,
-			Func: func() *JobResult {
-				return e.deleteLabelsForRepo(repo, labels)
-			},
-		}
-	}
+// SetSyncScope makes sync only create, update and delete labels in the scope,
+// such as those of one team, and leave the others alone
+func (e *Executor) SetSyncScope(scope option.LabelScope) {
+	e.syncScope = scope
+}
 
-	results := wp.Run(jobs)
-	wp.ClearProgress()
+// SetRepoFilter sets the filter selecting the repositories an owner/* wildcard
+// or the repository search query expands to
+func (e *Executor) SetRepoFilter(filter option.RepoFilter) {
+	e.repoFilter = filter
+}
 
-	// Output all results together
-	er := NewExecutionResult()
-	for i, result := range results {
-		_, _ = fmt.Fprint(out, result.Output)
-		er.AddRepoResult(&RepoResult{
-			Repo:   repos[i].String(),
-			Errors: result.Errors,
-		})
-	}
+// SetRepoQuery adds the repositories matching a GitHub repository search
+// query (e.g., "org:acme topic:backend") to the repositories of every command
+func (e *Executor) SetRepoQuery(query string) {
+	e.repoQuery = query
+}
 
-	_, _ = fmt.Fprintf(out, "\n%s\n", er.Summary())
-	return er.Err()
+// SetRepoExcludes sets OWNER/REPO glob patterns of repositories to leave out
+// of every command, after wildcards are expanded
+func (e *Executor) SetRepoExcludes(patterns option.RepoPatterns) {
+	e.repoExcludes = e.localPatterns(patterns)
 }
 
-func (e *Executor) deleteLabelsForRepo(repo option.Repo, labels []string) *JobResult {
-	var output strings.Builder
-	var errors []error
+// resolveRepos expands owner/* wildcards and the repository search query with
+// expandRepos. Repositories matching the exclusions are returned separately,
+// so they can be reported.
+func (e *Executor) resolveRepos(ctx context.Context, repos []option.Repo) (resolved, excluded []option.Repo, err error) {
+	expanded, err := e.expandRepos(ctx, repos, e.repoQuery, e.repoFilter)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	for _, label := range labels {
-		err := e.api.DeleteLabel(label, repo)
-		if err != nil {
-			fmt.Fprintf(&output, "Failed to delete label %q for repository %q: %v\n", label, repo, err)
-			errors = append(errors, err)
+	for _, repo := range expanded {
+		if e.repoExcludes.Match(repo) {
+			excluded = append(excluded, repo)
 			continue
 		}
-		fmt.Fprintf(&output, "Deleted label %q for repository %q\n", label, repo)
+		resolved = append(resolved, repo)
 	}
 
-	return &JobResult{
-		Output:  output.String(),
-		Success: len(errors) == 0,
-		Errors:  errors,
+	if len(resolved) == 0 {
+		if len(excluded) > 0 {
+			return nil, nil, fmt.Errorf("no repositories matched: all %d excluded", len(excluded))
+		}
+		return nil, nil, fmt.Errorf("no repositories matched")
 	}
+	return resolved, excluded, nil
 }
 
-// Sync sync labels across multiple repositories
-func (e *Executor) Sync(out io.Writer, repos []option.Repo, labels []option.Label) error {
-	// Dry-run mode: execute sequentially with immediate output
-	if e.dryRun {
-		return e.syncDryRun(out, repos, labels)
+// printExcluded reports the repositories left out by the exclusions, for
+// commands without a plan to list them in
+func printExcluded(out io.Writer, excluded []option.Repo) {
+	for _, repo := range excluded {
+		_, _ = fmt.Fprintf(out, "Skipped repository %q: excluded\n", repo)
 	}
-
-	// Normal mode: execute in parallel
-	return e.syncParallel(out, repos, labels)
 }
 
-func (e *Executor) syncDryRun(out io.Writer, repos []option.Repo, labels []option.Label) error {
-	var hasError bool
+// expandRepos expands owner/* wildcards to the repositories of the owner and
+// adds the results of the repository search query, keeping those that pass
+// filter. The query only searches the default host. Topics and languages of
+// filter are searched for, rather than listing every repository of the owner
+// to find the few that have them. Repositories named explicitly are kept as is. Duplicates, compared
+// ignoring case as GitHub does, are removed, keeping the first occurrence. The
+// API clients of the hosts of the
+// repositories are created along the way.
+func (e *Executor) expandRepos(ctx context.Context, repos []option.Repo, query string, filter option.RepoFilter) ([]option.Repo, error) {
+	var expanded []option.Repo
+	seen := make(map[string]bool)
+	add := func(repo option.Repo) {
+		key := strings.ToLower(repo.String())
+		if !seen[key] {
+			seen[key] = true
+			expanded = append(expanded, repo)
+		}
+	}
+
 	for _, repo := range repos {
-		existingLabels, err := e.api.ListLabels(repo)
-		if err != nil {
-			_, _ = fmt.Fprintf(out, "Failed to list labels for repository %q: %v\n", repo, err)
-			hasError = true
+		repo = e.localRepo(repo)
+		if err := e.connect(repo); err != nil {
+			return nil, err
+		}
+		if !repo.IsWildcard() {
+			add(repo)
 			continue
 		}
 
-		// Delete labels not in the new set
-		for _, existing := range existingLabels {
-			if labelExists(existing.Name, labels) {
-				continue
-			}
-			_, _ = fmt.Fprintf(out, "Would delete label %q for repository %q\n", existing.Name, repo)
+		owner := repo.Owner
+		if repo.Host != "" {
+			owner = repo.Host + "/" + repo.Owner
 		}
-
-		// Create or update labels
-		for _, label := range labels {
-			if labelExists(label.Name, existingLabels) {
-				_, _ = fmt.Fprintf(out, "Would update label %q for repository %q\n", label, repo)
-			} else {
-				_, _ = fmt.Fprintf(out, "Would create label %q for repository %q\n", label, repo)
+		var ownerRepos []option.Repository
+		var err error
+		if qualifiers := filter.SearchQualifiers(); qualifiers != "" {
+			ownerRepos, err = e.apiFor(repo).SearchRepositories(ctx, ownerSearchQuery(repo.Owner, qualifiers, filter))
+		} else {
+			ownerRepos, err = e.apiFor(repo).ListRepositories(ctx, repo.Owner)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories of %q: %v", owner, err)
+		}
+		for _, r := range ownerRepos {
+			r.Host = repo.Host
+			if filter.Match(r) {
+				add(r.Repo)
 			}
 		}
 	}
-	if hasError {
-		return fmt.Errorf("some operations failed")
-	}
-	return nil
-}
 
-func (e *Executor) syncParallel(out io.Writer, repos []option.Repo, labels []option.Label) error {
-	wp := NewWorkerPool(out)
-	jobs := make([]Job, len(repos))
-
-	for i, repo := range repos {
-		jobs[i] = Job{
-			ID: i,
-			Func: func() *JobResult {
-				return e.syncLabelsForRepo(repo, labels)
-			},
+	if query != "" {
+		if qualifiers := filter.SearchQualifiers(); qualifiers != "" {
+			query += " " + qualifiers
+		}
+		found, err := e.api.SearchRepositories(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search repositories with %q: %v", query, err)
+		}
+		for _, r := range found {
+			if filter.Match(r) {
+				add(r.Repo)
+			}
 		}
 	}
 
-	results := wp.Run(jobs)
-	wp.ClearProgress()
+	return expanded, nil
+}
 
-	// Output all results together
-	er := NewExecutionResult()
-	for i, result := range results {
-		_, _ = fmt.Fprint(out, result.Output)
-		er.AddRepoResult(&RepoResult{
-			Repo:   repos[i].String(),
-			Errors: result.Errors,
-		})
+// ownerSearchQuery returns the repository search query for the repositories of
+// owner matching qualifiers. Search leaves out forks unless asked for them, so
+// they are, as listing the repositories of the owner would return them.
+func ownerSearchQuery(owner, qualifiers string, filter option.RepoFilter) string {
+	query := "user:" + owner + " " + qualifiers
+	if filter.SkipArchived {
+		query += " archived:false"
 	}
-
-	_, _ = fmt.Fprintf(out, "\n%s\n", er.Summary())
-	return er.Err()
+	if !filter.SkipForks {
+		query += " fork:true"
+	}
+	return query
 }
 
-func (e *Executor) syncLabelsForRepo(repo option.Repo, labels []option.Label) *JobResult {
-	var output strings.Builder
-	var errors []error
-
-	existingLabels, err := e.api.ListLabels(repo)
+// Rename renames a label across multiple repositories, keeping it on its items.
+// Empty color and description keep the current values. In repositories where
+// the new name already exists, the label is merged into the existing one.
+func (e *Executor) Rename(ctx context.Context, out io.Writer, repos []option.Repo, fromLabel, toLabel, color, description string) error {
+	plan, err := e.buildPlan(ctx, out, CommandRename, repos, func(ctx context.Context, repo option.Repo) *RepoPlan {
+		return e.planRename(ctx, repo, fromLabel, toLabel, color, description)
+	})
 	if err != nil {
-		fmt.Fprintf(&output, "Failed to list labels for repository %q: %v\n", repo, err)
-		errors = append(errors, err)
-		return &JobResult{
-			Output:  output.String(),
-			Success: false,
-			Errors:  errors,
-		}
-	}
-
-	// Delete labels not in the new set
-	for _, existing := range existingLabels {
-		if labelExists(existing.Name, labels) {
-			continue
-		}
-
-		err = e.api.DeleteLabel(existing.Name, repo)
-		if err != nil {
-			fmt.Fprintf(&output, "Failed to delete label %q for repository %q: %v\n", existing.Name, repo, err)
-			errors = append(errors, err)
-		} else {
-			fmt.Fprintf(&output, "Deleted label %q for repository %q\n", existing.Name, repo)
-		}
+		return err
 	}
+	return e.run(ctx, out, plan)
+}
 
-	// Create or update labels
-	for _, label := range labels {
-		if labelExists(label.Name, existingLabels) {
-			err = e.api.UpdateLabel(label, repo)
-			if err != nil {
-				fmt.Fprintf(&output, "Failed to update label %q for repository %q: %v\n", label, repo, err)
-				errors = append(errors, err)
-			} else {
-				fmt.Fprintf(&output, "Updated label %q for repository %q\n", label, repo)
-			}
-		} else {
-			err = e.api.CreateLabel(label, repo)
-			if err != nil {
-				fmt.Fprintf(&output, "Failed to create label %q for repository %q: %v\n", label, repo, err)
-				errors = append(errors, err)
-			} else {
-				fmt.Fprintf(&output, "Created label %q for repository %q\n", label, repo)
-			}
-		}
+// run prints the plan in dry-run mode, saves it when a plan path is set and applies it otherwise
+func (e *Executor) run(ctx context.Context, out io.Writer, plan *Plan) error {
+	if e.planPath != "" {
+		return e.savePlan(out, plan)
 	}
-
-	return &JobResult{
-		Output:  output.String(),
-		Success: len(errors) == 0,
-		Errors:  errors,
+	if e.dryRun {
+		return plan.Print(out)
 	}
+	return e.Apply(ctx, out, plan)
 }
 
-// List lists labels across multiple repositories
-func (e *Executor) List(out io.Writer, repos []option.Repo) error {
-	wp := NewWorkerPool(out)
-	jobs := make([]Job, len(repos))
+// Apply executes every operation of the plan in parallel, one job per
+// repository. Once ctx is canceled, repositories in progress stop after their
+// current label and no other repository is started; the summary then reports
+// the repositories stopped partway and those never started.
+func (e *Executor) Apply(ctx context.Context, out io.Writer, plan *Plan) error {
+	wp := e.newWorkerPool(out)
+	jobs := make([]Job, len(plan.Repos))
 
-	for i, repo := range repos {
+	for i, rp := range plan.Repos {
 		jobs[i] = Job{
-			ID: i,
-			Func: func() *JobResult {
-				return e.listLabelsForRepo(repo)
+			ID:  i,
+			Key: e.ownerKey(rp.Repo),
+			Func: func(ctx context.Context) *JobResult {
+				return e.applyRepoPlan(ctx, rp)
 			},
 		}
 	}
 
-	results := wp.Run(jobs)
+	results := wp.Run(ctx, jobs)
 	wp.ClearProgress()
 
 	// Output all results together
 	er := NewExecutionResult()
 	for i, result := range results {
 		_, _ = fmt.Fprint(out, result.Output)
-		er.AddRepoResult(&RepoResult{
-			Repo:   repos[i].String(),
-			Errors: result.Errors,
-		})
+		er.AddJobResult(plan.Repos[i].Repo.String(), result)
+	}
+	for _, repo := range plan.Excluded {
+		er.AddExcluded(repo.String())
 	}
 
 	_, _ = fmt.Fprintf(out, "\n%s\n", er.Summary())
+	_, _ = fmt.Fprint(out, er.CountsSummary())
+	_, _ = fmt.Fprint(out, er.NotStartedSummary())
 	return er.Err()
 }
 
-func (e *Executor) listLabelsForRepo(repo option.Repo) *JobResult {
+func (e *Executor) applyRepoPlan(ctx context.Context, rp *RepoPlan) *JobResult {
 	var output strings.Builder
 	var errors []error
+	var counts OperationCounts
 
-	labels, err := e.api.ListLabels(repo)
-	if err != nil {
-		fmt.Fprintf(&output, "Failed to list labels for repository %q: %v\n", repo, err)
-		errors = append(errors, err)
+	if rp.Err != nil {
+		fmt.Fprintf(&output, "%v\n", rp.Err)
 		return &JobResult{
 			Output:  output.String(),
 			Success: false,
-			Errors:  errors,
-		}
-	}
-
-	if len(labels) == 0 {
-		fmt.Fprintf(&output, "Repository %q has no labels\n", repo)
-	} else {
-		fmt.Fprintf(&output, "Labels for repository %q:\n", repo)
-		for _, label := range labels {
-			if label.Description == "" {
-				fmt.Fprintf(&output, "  %s (#%s)\n", label.Name, label.Color)
-			} else {
-				fmt.Fprintf(&output, "  %s (#%s) - %s\n", label.Name, label.Color, label.Description)
+			Errors:  []error{rp.Err},
+		}
+	}
+
+	repo := rp.Repo
+	client := e.apiFor(repo)
+	preposition := rp.deletePreposition()
+
+	// Relabel results per source label; its deletion is skipped if any item failed
+	relabelSucceeded := make(map[string]int)
+	relabelFailed := make(map[string]int)
+
+	// Label changes are sent in batches. pending holds the operations of the
+	// next batch, which is sent before relabeling items, as relabeling may
+	// depend on earlier changes.
+	var pending []Operation
+	// interrupted is set once ctx is canceled with operations left, which
+	// are then not applied
+	var interrupted bool
+	flush := func() {
+		var changes []option.LabelChange
+		for _, op := range pending {
+			if change, ok := labelChange(op); ok {
+				changes = append(changes, change)
 			}
 		}
-	}
+		changeErrs := e.applyLabelChanges(ctx, client, repo, changes)
 
-	return &JobResult{
-		Output:  output.String(),
-		Success: true,
-		Errors:  errors,
-	}
-}
+		i := 0
+		for _, op := range pending {
+			if op.Type == OperationSkip {
+				fmt.Fprintf(&output, "Skipped deleting label %q %s repository %q: used by %s\n", op.Before.Name, preposition, repo, op.Usage)
+				continue
+			}
+			if i == len(changeErrs) {
+				// Not sent, as ctx was canceled before
+				interrupted = true
+				continue
+			}
+			err := changeErrs[i]
+			i++
+			if isCanceled(err) {
+				interrupted = true
+				continue
+			}
 
-// Empty empties labels across multiple repositories
-func (e *Executor) Empty(out io.Writer, repos []option.Repo) error {
-	// Dry-run mode: execute sequentially with immediate output
-	if e.dryRun {
-		return e.emptyDryRun(out, repos)
-	}
+			switch op.Type {
+			case OperationCreate:
+				label := *op.After
+				if err != nil {
+					// If force flag is set and label already exists, try to update it
+					if op.Force && api.IsAlreadyExists(err) {
+						e.mutations.wait(ctx, 1)
+						err = client.UpdateLabel(ctx, label, repo)
+						if isCanceled(err) {
+							interrupted = true
+							continue
+						}
+						if err != nil {
+							fmt.Fprintf(&output, "Failed to update label %q for repository %q: %v\n", label, repo, err)
+							errors = append(errors, err)
+							continue
+						}
+						fmt.Fprintf(&output, "Updated label %q for repository %q\n", label, repo)
+						counts.Updated++
+						continue
+					}
+
+					fmt.Fprintf(&output, "Failed to create label %q for repository %q: %v\n", label, repo, err)
+					errors = append(errors, err)
+					continue
+				}
+				fmt.Fprintf(&output, "Created label %q for repository %q\n", label, repo)
+				counts.Created++
 
-	// Normal mode: execute in parallel
-	return e.emptyParallel(out, repos)
-}
+			case OperationUpdate:
+				label := *op.After
+				if err != nil {
+					fmt.Fprintf(&output, "Failed to update label %q for repository %q: %v\n", label, repo, err)
+					errors = append(errors, err)
+					continue
+				}
+				fmt.Fprintf(&output, "Updated label %q for repository %q\n", label, repo)
+				counts.Updated++
 
-func (e *Executor) emptyDryRun(out io.Writer, repos []option.Repo) error {
-	var hasError bool
-	for _, repo := range repos {
-		labels, err := e.api.ListLabels(repo)
-		if err != nil {
-			_, _ = fmt.Fprintf(out, "Failed to list labels for repository %q: %v\n", repo, err)
-			hasError = true
-			continue
-		}
+			case OperationRename:
+				if err != nil {
+					fmt.Fprintf(&output, "Failed to rename label %q to %q for repository %q: %v\n", op.Before.Name, op.After.Name, repo, err)
+					errors = append(errors, err)
+					continue
+				}
+				fmt.Fprintf(&output, "Renamed label %q to %q for repository %q\n", op.Before.Name, op.After.Name, repo)
+				counts.Updated++
 
-		for _, label := range labels {
-			_, _ = fmt.Fprintf(out, "Would delete label %q for repository %q\n", label.Name, repo)
+			case OperationDelete:
+				label := op.Before.Name
+				if err != nil {
+					fmt.Fprintf(&output, "Failed to delete label %q %s repository %q: %v\n", label, preposition, repo, err)
+					errors = append(errors, err)
+					continue
+				}
+				fmt.Fprintf(&output, "Deleted label %q %s repository %q\n", label, preposition, repo)
+				counts.Deleted++
+			}
 		}
+		pending = nil
 	}
-	if hasError {
-		return fmt.Errorf("some operations failed")
-	}
-	return nil
-}
 
-func (e *Executor) emptyParallel(out io.Writer, repos []option.Repo) error {
-	wp := NewWorkerPool(out)
-	jobs := make([]Job, len(repos))
-
-	for i, repo := range repos {
-		jobs[i] = Job{
-			ID: i,
-			Func: func() *JobResult {
-				return e.emptyLabelsForRepo(repo)
-			},
+	for _, op := range rp.Operations {
+		if ctx.Err() != nil {
+			interrupted = true
+			break
 		}
-	}
+		switch op.Type {
+		case OperationCreate, OperationUpdate, OperationRename, OperationSkip:
+			pending = append(pending, op)
 
-	results := wp.Run(jobs)
-	wp.ClearProgress()
+		case OperationUnchanged:
+			counts.Unchanged++
 
-	// Output all results together
-	er := NewExecutionResult()
-	for i, result := range results {
-		_, _ = fmt.Fprint(out, result.Output)
-		er.AddRepoResult(&RepoResult{
-			Repo:   repos[i].String(),
-			Errors: result.Errors,
-		})
-	}
+		case OperationDelete:
+			label := op.Before.Name
+			if failed := relabelFailed[label]; failed > 0 {
+				flush()
+				fmt.Fprintf(&output, "Skipped deleting label %q from repository %q: %d items succeeded, %d items failed\n", label, repo, relabelSucceeded[label], failed)
+				continue
+			}
+			pending = append(pending, op)
 
-	_, _ = fmt.Fprintf(out, "\n%s\n", er.Summary())
-	return er.Err()
-}
+		case OperationRelabel:
+			flush()
+			fromLabel, toLabel, item := op.Before.Name, op.After.Name, op.Item
 
-func (e *Executor) emptyLabelsForRepo(repo option.Repo) *JobResult {
-	var output strings.Builder
-	var errors []error
+			// Add target label
+			e.mutations.wait(ctx, 1)
+			err := client.AddLabelsToLabelable(ctx, item.ID, []option.GraphQLID{rp.LabelIDs[toLabel]})
+			if isCanceled(err) {
+				interrupted = true
+				continue
+			}
+			if err != nil {
+				fmt.Fprintf(&output, "Failed to add label %q to %s #%d in repository %q: %v\n", toLabel, item.Type, item.Number, repo, err)
+				errors = append(errors, err)
+				relabelFailed[fromLabel]++
+				continue
+			}
+			fmt.Fprintf(&output, "Added label %q to %s #%d in repository %q\n", toLabel, item.Type, item.Number, repo)
 
-	labels, err := e.api.ListLabels(repo)
-	if err != nil {
-		fmt.Fprintf(&output, "Failed to list labels for repository %q: %v\n", repo, err)
-		errors = append(errors, err)
-		return &JobResult{
-			Output:  output.String(),
-			Success: false,
-			Errors:  errors,
+			// Remove source label
+			e.mutations.wait(ctx, 1)
+			err = client.RemoveLabelsFromLabelable(ctx, item.ID, []option.GraphQLID{rp.LabelIDs[fromLabel]})
+			if isCanceled(err) {
+				interrupted = true
+				continue
+			}
+			if err != nil {
+				fmt.Fprintf(&output, "Failed to remove label %q from %s #%d in repository %q (target label %q was added): %v\n", fromLabel, item.Type, item.Number, repo, toLabel, err)
+				errors = append(errors, err)
+				relabelFailed[fromLabel]++
+				continue
+			}
+			fmt.Fprintf(&output, "Removed label %q from %s #%d in repository %q\n", fromLabel, item.Type, item.Number, repo)
+			relabelSucceeded[fromLabel]++
 		}
 	}
-
-	for _, label := range labels {
-		err := e.api.DeleteLabel(label.Name, repo)
-		if err != nil {
-			fmt.Fprintf(&output, "Failed to delete label %q for repository %q: %v\n", label.Name, repo, err)
-			errors = append(errors, err)
-		} else {
-			fmt.Fprintf(&output, "Deleted label %q for repository %q\n", label.Name, repo)
-		}
+	flush()
+	if interrupted {
+		fmt.Fprintf(&output, "Interrupted before finishing the operations for repository %q\n", repo)
 	}
 
 	return &JobResult{
-		Output:  output.String(),
-		Success: len(errors) == 0,
-		Errors:  errors,
+		Output:      output.String(),
+		Success:     len(errors) == 0,
+		Errors:      errors,
+		Counts:      counts,
+		Interrupted: interrupted,
 	}
 }
 
-// Merge merges a source label into a target label across multiple repositories.
-// It adds the target label to all items with the source label, removes the source label,
-// and then deletes the source label from the repository.
-func (e *Executor) Merge(out io.Writer, repos []option.Repo, fromLabel, toLabel string) error {
-	// Dry-run mode: execute sequentially with immediate output
-	if e.dryRun {
-		return e.mergeDryRun(out, repos, fromLabel, toLabel)
-	}
-
-	// Normal mode: execute in parallel
-	return e.mergeParallel(out, repos, fromLabel, toLabel)
+// isCanceled returns true if err is from a request canceled with its context,
+// which left the operation not done
+func isCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
 }
 
-func (e *Executor) mergeDryRun(out io.Writer, repos []option.Repo, fromLabel, toLabel string) error {
-	var hasError bool
-	for _, repo := range repos {
-		// Check if source label exists
-		_, err := e.api.GetLabelID(repo, fromLabel)
-		if err != nil {
-			_, _ = fmt.Fprintf(out, "Failed to find source label %q in repository %q: %v\n", fromLabel, repo, err)
-			hasError = true
-			continue
-		}
-
-		// Check if target label exists
-		_, err = e.api.GetLabelID(repo, toLabel)
-		if err != nil {
-			_, _ = fmt.Fprintf(out, "Failed to find target label %q in repository %q: %v\n", toLabel, repo, err)
-			hasError = true
-			continue
-		}
-
-		// Search for items with source label
-		labelables, err := e.api.SearchLabelables(repo, fromLabel)
-		if err != nil {
-			_, _ = fmt.Fprintf(out, "Failed to search for items with label %q in repository %q: %v\n", fromLabel, repo, err)
-			hasError = true
-			continue
-		}
+// labelChange returns the label change an operation makes, false if it makes
+// none
+func labelChange(op Operation) (option.LabelChange, bool) {
+	switch op.Type {
+	case OperationCreate:
+		return option.LabelChange{Type: option.LabelChangeCreate, Label: *op.After}, true
+	case OperationUpdate:
+		return option.LabelChange{Type: option.LabelChangeUpdate, Label: *op.After}, true
+	case OperationRename:
+		label := *op.After
+		label.Name = op.Before.Name
+		label.NewName = op.After.Name
+		return option.LabelChange{Type: option.LabelChangeUpdate, Label: label}, true
+	case OperationDelete:
+		return option.LabelChange{Type: option.LabelChangeDelete, Label: option.Label{Name: op.Before.Name}}, true
+	}
+	return option.LabelChange{}, false
+}
 
-		if len(labelables) == 0 {
-			_, _ = fmt.Fprintf(out, "No items found with label %q in repository %q\n", fromLabel, repo)
-		} else {
-			for _, item := range labelables {
-				_, _ = fmt.Fprintf(out, "Would add label %q to %s #%d in repository %q\n", toLabel, item.Type, item.Number, repo)
-				_, _ = fmt.Fprintf(out, "Would remove label %q from %s #%d in repository %q\n", fromLabel, item.Type, item.Number, repo)
-			}
-		}
+// batchLimit returns the maximum number of label changes or repositories in
+// one request
+func (e *Executor) batchLimit() int {
+	if e.batchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return e.batchSize
+}
 
-		_, _ = fmt.Fprintf(out, "Would delete label %q from repository %q\n", fromLabel, repo)
+// applyLabelChanges sends the changes in batches of at most batchSize changes,
+// and of no more than the mutations allowed a minute, returning the error of
+// each change sent. Once ctx is canceled no other batch is sent, so fewer
+// errors than changes are returned.
+func (e *Executor) applyLabelChanges(ctx context.Context, client api.APIClient, repo option.Repo, changes []option.LabelChange) []error {
+	limit := e.batchLimit()
+	if e.maxMutationsPerMinute > 0 {
+		limit = min(limit, e.maxMutationsPerMinute)
 	}
-	if hasError {
-		return fmt.Errorf("some operations failed")
+	errs := make([]error, 0, len(changes))
+	for batch := range slices.Chunk(changes, limit) {
+		e.mutations.wait(ctx, len(batch))
+		if ctx.Err() != nil {
+			break
+		}
+		errs = append(errs, client.ApplyLabelChanges(ctx, repo, batch)...)
 	}
-	return nil
+	return errs
 }
 
-func (e *Executor) mergeParallel(out io.Writer, repos []option.Repo, fromLabel, toLabel string) error {
-	wp := NewWorkerPool(out)
+// List lists labels across multiple repositories
+func (e *Executor) List(ctx context.Context, out io.Writer, repos []option.Repo) error {
+	repos, excluded, err := e.resolveRepos(ctx, repos)
+	if err != nil {
+		return err
+	}
+	printExcluded(out, excluded)
+
+	e.prefetchLabels(ctx, repos)
+	defer e.clearPrefetched()
+
+	wp := e.newWorkerPool(out)
 	jobs := make([]Job, len(repos))
 
 	for i, repo := range repos {
 		jobs[i] = Job{
-			ID: i,
-			Func: func() *JobResult {
-				return e.mergeLabelsForRepo(repo, fromLabel, toLabel)
+			ID:  i,
+			Key: e.ownerKey(repo),
+			Func: func(ctx context.Context) *JobResult {
+				return e.listLabelsForRepo(ctx, repo)
 			},
 		}
 	}
 
-	results := wp.Run(jobs)
+	results := wp.Run(ctx, jobs)
 	wp.ClearProgress()
 
 	// Output all results together
 	er := NewExecutionResult()
 	for i, result := range results {
 		_, _ = fmt.Fprint(out, result.Output)
-		er.AddRepoResult(&RepoResult{
-			Repo:   repos[i].String(),
-			Errors: result.Errors,
-		})
+		er.AddJobResult(repos[i].String(), result)
 	}
 
 	_, _ = fmt.Fprintf(out, "\n%s\n", er.Summary())
+	_, _ = fmt.Fprint(out, er.NotStartedSummary())
 	return er.Err()
 }
 
-func (e *Executor) mergeLabelsForRepo(repo option.Repo, fromLabel, toLabel string) *JobResult {
+func (e *Executor) listLabelsForRepo(ctx context.Context, repo option.Repo) *JobResult {
 	var output strings.Builder
 	var errors []error
 
-	// Get source label ID
-	fromLabelID, err := e.api.GetLabelID(repo, fromLabel)
+	labels, err := e.listLabels(ctx, repo)
 	if err != nil {
-		fmt.Fprintf(&output, "Failed to find source label %q in repository %q: %v\n", fromLabel, repo, err)
-		errors = append(errors, err)
-		return &JobResult{
-			Output:  output.String(),
-			Success: false,
-			Errors:  errors,
-		}
-	}
-
-	// Get target label ID
-	toLabelID, err := e.api.GetLabelID(repo, toLabel)
-	if err != nil {
-		fmt.Fprintf(&output, "Failed to find target label %q in repository %q: %v\n", toLabel, repo, err)
-		errors = append(errors, err)
-		return &JobResult{
-			Output:  output.String(),
-			Success: false,
-			Errors:  errors,
-		}
-	}
-
-	// Search for items with source label
-	labelables, err := e.api.SearchLabelables(repo, fromLabel)
-	if err != nil {
-		fmt.Fprintf(&output, "Failed to search for items with label %q in repository %q: %v\n", fromLabel, repo, err)
+		fmt.Fprintf(&output, "Failed to list labels for repository %q: %v\n", repo, err)
 		errors = append(errors, err)
 		return &JobResult{
 			Output:  output.String(),
@@ -641,58 +688,44 @@ func (e *Executor) mergeLabelsForRepo(repo option.Repo, fromLabel, toLabel strin
 		}
 	}
 
-	// Process each item
-	successCount := 0
-	failCount := 0
-	for _, item := range labelables {
-		// Add target label
-		err = e.api.AddLabelsToLabelable(item.ID, []option.GraphQLID{toLabelID})
-		if err != nil {
-			fmt.Fprintf(&output, "Failed to add label %q to %s #%d in repository %q: %v\n", toLabel, item.Type, item.Number, repo, err)
-			errors = append(errors, err)
-			failCount++
-			continue
-		}
-		fmt.Fprintf(&output, "Added label %q to %s #%d in repository %q\n", toLabel, item.Type, item.Number, repo)
-
-		// Remove source label
-		err = e.api.RemoveLabelsFromLabelable(item.ID, []option.GraphQLID{fromLabelID})
-		if err != nil {
-			fmt.Fprintf(&output, "Failed to remove label %q from %s #%d in repository %q (target label %q was added): %v\n", fromLabel, item.Type, item.Number, repo, toLabel, err)
-			errors = append(errors, err)
-			failCount++
-			continue
-		}
-		fmt.Fprintf(&output, "Removed label %q from %s #%d in repository %q\n", fromLabel, item.Type, item.Number, repo)
-		successCount++
-	}
-
-	// Only delete source label if all items were processed successfully
-	if failCount > 0 {
-		fmt.Fprintf(&output, "Skipped deleting label %q from repository %q: %d items succeeded, %d items failed\n", fromLabel, repo, successCount, failCount)
+	if len(labels) == 0 {
+		fmt.Fprintf(&output, "Repository %q has no labels\n", repo)
 	} else {
-		err = e.api.DeleteLabel(fromLabel, repo)
-		if err != nil {
-			fmt.Fprintf(&output, "Failed to delete label %q from repository %q: %v\n", fromLabel, repo, err)
-			errors = append(errors, err)
-		} else {
-			fmt.Fprintf(&output, "Deleted label %q from repository %q\n", fromLabel, repo)
+		fmt.Fprintf(&output, "Labels for repository %q:\n", repo)
+		for _, label := range labels {
+			if label.Description == "" {
+				fmt.Fprintf(&output, "  %s (#%s)\n", label.Name, label.Color)
+			} else {
+				fmt.Fprintf(&output, "  %s (#%s) - %s\n", label.Name, label.Color, label.Description)
+			}
 		}
 	}
 
 	return &JobResult{
 		Output:  output.String(),
-		Success: len(errors) == 0,
+		Success: true,
 		Errors:  errors,
 	}
 }
 
-// labelExists checks if a label name exists in a slice of labels
-func labelExists(name string, labels []option.Label) bool {
+// indexOfLabel returns the index of the label with the given name in a slice
+// of labels, or -1 if it is not found. Names are compared case-insensitively.
+func indexOfLabel(name string, labels []option.Label) int {
+	for i, label := range labels {
+		if strings.EqualFold(name, label.Name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findLabel returns the label with the given name from a slice of labels.
+// Label names are compared case-insensitively, as GitHub does.
+func findLabel(name string, labels []option.Label) (option.Label, bool) {
 	for _, label := range labels {
-		if name == label.Name {
-			return true
+		if strings.EqualFold(name, label.Name) {
+			return label, true
 		}
 	}
-	return false
+	return option.Label{}, false
 }
@@ -2,12 +2,17 @@ package executor
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/tnagatomi/gh-fuda/api"
 	"github.com/tnagatomi/gh-fuda/internal/mock"
 	"github.com/tnagatomi/gh-fuda/option"
@@ -53,7 +58,7 @@ func containsAllCalls(got, want []struct {
 	found := make([]bool, len(want))
 	for _, g := range got {
 		for i, w := range want {
-			if !found[i] && g.Label == w.Label && g.Repo == w.Repo {
+			if !found[i] && reflect.DeepEqual(g.Label, w.Label) && g.Repo == w.Repo {
 				found[i] = true
 				break
 			}
@@ -78,7 +83,7 @@ func containsAllDeleteCalls(got, want []struct {
 	found := make([]bool, len(want))
 	for _, g := range got {
 		for i, w := range want {
-			if !found[i] && g.Label == w.Label && g.Repo == w.Repo {
+			if !found[i] && reflect.DeepEqual(g.Label, w.Label) && g.Repo == w.Repo {
 				found[i] = true
 				break
 			}
@@ -159,6 +164,7 @@ Created label "enhancement" for repository "tnagatomi/mock-repo"
 Created label "question" for repository "tnagatomi/mock-repo"
 
 Summary: all operations completed successfully
+  tnagatomi/mock-repo: 3 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: false,
 			wantCall: []struct {
@@ -197,6 +203,8 @@ Created label "enhancement" for repository "tnagatomi/mock-repo-2"
 Created label "question" for repository "tnagatomi/mock-repo-2"
 
 Summary: all operations completed successfully
+  tnagatomi/mock-repo-1: 3 created, 0 updated, 0 deleted, 0 unchanged
+  tnagatomi/mock-repo-2: 3 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: false,
 			wantCall: []struct {
@@ -232,6 +240,7 @@ Summary: all operations completed successfully
 Failed to create label "enhancement" for repository "tnagatomi/non-existent-repo": repository not found
 
 Summary: 0 repositories succeeded, 1 failed
+  tnagatomi/non-existent-repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantCall: []struct {
@@ -261,6 +270,7 @@ Summary: 0 repositories succeeded, 1 failed
 			wantOut: `Failed to create label "bug" for repository "tnagatomi/private-repo": forbidden
 
 Summary: 0 repositories succeeded, 1 failed
+  tnagatomi/private-repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantCall: []struct {
@@ -296,6 +306,9 @@ Failed to create label "bug" for repository "tnagatomi/repo-2": repository not f
 Created label "bug" for repository "tnagatomi/repo-3"
 
 Summary: 2 repositories succeeded, 1 failed
+  tnagatomi/repo-1: 1 created, 0 updated, 0 deleted, 0 unchanged
+  tnagatomi/repo-2: 0 created, 0 updated, 0 deleted, 0 unchanged
+  tnagatomi/repo-3: 1 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantCall: []struct {
@@ -358,6 +371,7 @@ Would create label "question" for repository "tnagatomi/mock-repo"
 			wantOut: `Updated label "bug" for repository "tnagatomi/mock-repo"
 
 Summary: all operations completed successfully
+  tnagatomi/mock-repo: 0 created, 1 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: false,
 			wantCall: []struct {
@@ -393,6 +407,7 @@ Summary: all operations completed successfully
 			wantOut: `Failed to create label "bug" for repository "tnagatomi/mock-repo": label already exists
 
 Summary: 0 repositories succeeded, 1 failed
+  tnagatomi/mock-repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantCall: []struct {
@@ -425,6 +440,7 @@ Summary: 0 repositories succeeded, 1 failed
 			wantOut: `Failed to update label "bug" for repository "tnagatomi/mock-repo": forbidden
 
 Summary: 0 repositories succeeded, 1 failed
+  tnagatomi/mock-repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantCall: []struct {
@@ -468,6 +484,7 @@ Summary: 0 repositories succeeded, 1 failed
 Created label "enhancement" for repository "tnagatomi/mock-repo"
 
 Summary: all operations completed successfully
+  tnagatomi/mock-repo: 1 created, 1 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: false,
 			wantCall: []struct {
@@ -511,6 +528,43 @@ Would create label "enhancement" for repository "tnagatomi/mock-repo"
 				Repo  option.Repo
 			}{},
 		},
+		{
+			name:   "force flag - existing label with different case is renamed",
+			dryrun: false,
+			force:  true,
+			args: args{
+				repos: []option.Repo{
+					{Owner: "tnagatomi", Repo: "mock-repo"},
+				},
+				labels: []option.Label{
+					{Name: "Bug", Color: "ff0000", Description: "Bug"},
+				},
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return []option.Label{{Name: "bug", Color: "d73a4a"}}, nil
+				},
+				UpdateLabelFunc: func(label option.Label, repo option.Repo) error {
+					return nil
+				},
+			},
+			wantOut: `Renamed label "bug" to "Bug" for repository "tnagatomi/mock-repo"
+
+Summary: all operations completed successfully
+  tnagatomi/mock-repo: 0 created, 1 updated, 0 deleted, 0 unchanged
+`,
+			wantErr: false,
+			wantCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{
+				{Label: option.Label{Name: "bug", Description: "Bug", Color: "ff0000", NewName: "Bug"}, Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
+			},
+		},
 		{
 			name:   "dry-run with force flag - list labels fails",
 			dryrun: true,
@@ -545,7 +599,7 @@ Would create label "enhancement" for repository "tnagatomi/mock-repo"
 				dryRun: tt.dryrun,
 			}
 			out := &bytes.Buffer{}
-			err := e.Create(out, tt.args.repos, tt.args.labels, tt.force)
+			err := e.Create(context.Background(), out, tt.args.repos, tt.args.labels, tt.force)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
@@ -584,10 +638,11 @@ func TestDelete(t *testing.T) {
 		labels []string
 	}
 	tests := []struct {
-		name    string
-		dryrun  bool
-		args    args
-		mock    *mock.MockAPI
+		name     string
+		dryrun   bool
+		excludes option.RepoPatterns
+		args     args
+		mock     *mock.MockAPI
 		wantOut string
 		wantErr bool
 		wantCall []struct {
@@ -614,6 +669,7 @@ Deleted label "enhancement" for repository "tnagatomi/mock-repo"
 Deleted label "question" for repository "tnagatomi/mock-repo"
 
 Summary: all operations completed successfully
+  tnagatomi/mock-repo: 0 created, 0 updated, 3 deleted, 0 unchanged
 `,
 			wantErr: false,
 			wantCall: []struct {
@@ -648,6 +704,8 @@ Deleted label "enhancement" for repository "tnagatomi/mock-repo-2"
 Deleted label "question" for repository "tnagatomi/mock-repo-2"
 
 Summary: all operations completed successfully
+  tnagatomi/mock-repo-1: 0 created, 0 updated, 3 deleted, 0 unchanged
+  tnagatomi/mock-repo-2: 0 created, 0 updated, 3 deleted, 0 unchanged
 `,
 			wantErr: false,
 			wantCall: []struct {
@@ -680,6 +738,7 @@ Summary: all operations completed successfully
 Failed to delete label "enhancement" for repository "tnagatomi/non-existent-repo": repository not found
 
 Summary: 0 repositories succeeded, 1 failed
+  tnagatomi/non-existent-repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantCall: []struct {
@@ -707,6 +766,7 @@ Summary: 0 repositories succeeded, 1 failed
 			wantOut: `Failed to delete label "bug" for repository "tnagatomi/private-repo": forbidden
 
 Summary: 0 repositories succeeded, 1 failed
+  tnagatomi/private-repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantCall: []struct {
@@ -740,6 +800,9 @@ Failed to delete label "bug" for repository "tnagatomi/repo-2": repository not f
 Deleted label "bug" for repository "tnagatomi/repo-3"
 
 Summary: 2 repositories succeeded, 1 failed
+  tnagatomi/repo-1: 0 created, 0 updated, 1 deleted, 0 unchanged
+  tnagatomi/repo-2: 0 created, 0 updated, 0 deleted, 0 unchanged
+  tnagatomi/repo-3: 0 created, 0 updated, 1 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantCall: []struct {
@@ -751,6 +814,57 @@ Summary: 2 repositories succeeded, 1 failed
 				{Label: "bug", Repo: option.Repo{Owner: "tnagatomi", Repo: "repo-3"}},
 			},
 		},
+		{
+			name:     "excluded repository",
+			dryrun:   false,
+			excludes: option.RepoPatterns{"tnagatomi/legacy-*"},
+			args: args{
+				repos: []option.Repo{
+					{Owner: "tnagatomi", Repo: "mock-repo"},
+					{Owner: "tnagatomi", Repo: "legacy-app"},
+				},
+				labels: []string{"bug"},
+			},
+			mock: &mock.MockAPI{
+				DeleteLabelFunc: func(label string, repo option.Repo) error {
+					return nil
+				},
+			},
+			wantOut: `Deleted label "bug" for repository "tnagatomi/mock-repo"
+
+Summary: all operations completed successfully
+  tnagatomi/mock-repo: 0 created, 0 updated, 1 deleted, 0 unchanged
+  tnagatomi/legacy-app: excluded
+`,
+			wantErr: false,
+			wantCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{
+				{Label: "bug", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
+			},
+		},
+		{
+			name:     "excluded repository in dry-run",
+			dryrun:   true,
+			excludes: option.RepoPatterns{"tnagatomi/legacy-*"},
+			args: args{
+				repos: []option.Repo{
+					{Owner: "tnagatomi", Repo: "mock-repo"},
+					{Owner: "tnagatomi", Repo: "legacy-app"},
+				},
+				labels: []string{"bug"},
+			},
+			mock: &mock.MockAPI{},
+			wantOut: `Would skip repository "tnagatomi/legacy-app": excluded
+Would delete label "bug" for repository "tnagatomi/mock-repo"
+`,
+			wantErr: false,
+			wantCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{},
+		},
 		{
 			name:   "dry-run",
 			dryrun: true,
@@ -780,11 +894,12 @@ Would delete label "question" for repository "tnagatomi/mock-repo"
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := &Executor{
-				api:    tt.mock,
-				dryRun: tt.dryrun,
+				api:          tt.mock,
+				dryRun:       tt.dryrun,
+				repoExcludes: tt.excludes,
 			}
 			out := &bytes.Buffer{}
-			err := e.Delete(out, tt.args.repos, tt.args.labels)
+			err := e.Delete(context.Background(), out, tt.args.repos, tt.args.labels)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -815,8 +930,9 @@ Would delete label "question" for repository "tnagatomi/mock-repo"
 
 func TestSync(t *testing.T) {
 	type args struct {
-		repos  []option.Repo
-		labels []option.Label
+		repos      []option.Repo
+		labels     []option.Label
+		deleteUsed bool
 	}
 	tests := []struct {
 		name    string
@@ -884,6 +1000,8 @@ Updated label "bug" for repository "tnagatomi/mock-repo-2"
 Created label "enhancement" for repository "tnagatomi/mock-repo-2"
 
 Summary: all operations completed successfully
+  tnagatomi/mock-repo-1: 0 created, 2 updated, 1 deleted, 0 unchanged
+  tnagatomi/mock-repo-2: 1 created, 1 updated, 1 deleted, 0 unchanged
 `,
 			wantErr: false,
 			wantListCall: []option.Repo{
@@ -941,6 +1059,8 @@ Created label "enhancement" for repository "tnagatomi/mock-repo-1"
 Failed to list labels for repository "tnagatomi/non-existent-repo": repository not found
 
 Summary: 1 repositories succeeded, 1 failed
+  tnagatomi/mock-repo-1: 2 created, 0 updated, 0 deleted, 0 unchanged
+  tnagatomi/non-existent-repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantListCall: []option.Repo{
@@ -987,6 +1107,8 @@ Summary: 1 repositories succeeded, 1 failed
 Failed to update label "bug" for repository "tnagatomi/private-repo-2": forbidden
 
 Summary: 0 repositories succeeded, 2 failed
+  tnagatomi/private-repo-1: 0 created, 0 updated, 0 deleted, 0 unchanged
+  tnagatomi/private-repo-2: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantListCall: []option.Repo{
@@ -1046,6 +1168,9 @@ Failed to delete label "old-label" for repository "tnagatomi/repo-3": forbidden
 Created label "bug" for repository "tnagatomi/repo-3"
 
 Summary: 1 repositories succeeded, 2 failed
+  tnagatomi/repo-1: 1 created, 0 updated, 1 deleted, 0 unchanged
+  tnagatomi/repo-2: 0 created, 0 updated, 0 deleted, 0 unchanged
+  tnagatomi/repo-3: 1 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantListCall: []option.Repo{
@@ -1073,46 +1198,32 @@ Summary: 1 repositories succeeded, 2 failed
 			},
 		},
 		{
-			name:   "dry-run",
-			dryrun: true,
+			name:   "case-only name change renames in place",
+			dryrun: false,
 			args: args{
 				repos: []option.Repo{
-					{Owner: "tnagatomi", Repo: "mock-repo-1"},
-					{Owner: "tnagatomi", Repo: "mock-repo-2"},
+					{Owner: "tnagatomi", Repo: "mock-repo"},
 				},
 				labels: []option.Label{
-					{Name: "bug", Color: "ff0000", Description: "This is a bug"},
-					{Name: "enhancement", Color: "00ff00", Description: "This is an enhancement"},
+					{Name: "Bug", Color: "ff0000", Description: "This is a bug"},
 				},
 			},
 			mock: &mock.MockAPI{
-				CreateLabelFunc: func(label option.Label, repo option.Repo) error {
-					return nil
-				},
 				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
-					if repo.Repo == "mock-repo-1" {
-						return []option.Label{
-						{Name: "bug"},
-						{Name: "enhancement"},
-						{Name: "question"},
-					}, nil
-					}
-					return []option.Label{}, nil
+					return []option.Label{{Name: "bug", Color: "d73a4a"}}, nil
 				},
-				DeleteLabelFunc: func(label string, repo option.Repo) error {
+				UpdateLabelFunc: func(label option.Label, repo option.Repo) error {
 					return nil
 				},
 			},
-			wantOut: `Would delete label "question" for repository "tnagatomi/mock-repo-1"
-Would update label "bug" for repository "tnagatomi/mock-repo-1"
-Would update label "enhancement" for repository "tnagatomi/mock-repo-1"
-Would create label "bug" for repository "tnagatomi/mock-repo-2"
-Would create label "enhancement" for repository "tnagatomi/mock-repo-2"
+			wantOut: `Renamed label "bug" to "Bug" for repository "tnagatomi/mock-repo"
+
+Summary: all operations completed successfully
+  tnagatomi/mock-repo: 0 created, 1 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: false,
 			wantListCall: []option.Repo{
-				{Owner: "tnagatomi", Repo: "mock-repo-1"},
-				{Owner: "tnagatomi", Repo: "mock-repo-2"},
+				{Owner: "tnagatomi", Repo: "mock-repo"},
 			},
 			wantCreateCall: []struct {
 				Label option.Label
@@ -1121,209 +1232,273 @@ Would create label "enhancement" for repository "tnagatomi/mock-repo-2"
 			wantUpdateCall: []struct {
 				Label option.Label
 				Repo  option.Repo
-			}{},
+			}{
+				{Label: option.Label{Name: "bug", Color: "ff0000", Description: "This is a bug", NewName: "Bug"}, Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
+			},
 			wantDeleteCall: []struct {
 				Label string
 				Repo  option.Repo
 			}{},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			e := &Executor{
-				api:    tt.mock,
-				dryRun: tt.dryrun,
-			}
-			out := &bytes.Buffer{}
-			err := e.Sync(out, tt.args.repos, tt.args.labels)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Sync() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			gotOut := stripProgress(out.String())
-			// For parallel execution, compare output lines in sorted order
-			if sortedLines(gotOut) != nil && sortedLines(tt.wantOut) != nil {
-				gotLines := sortedLines(gotOut)
-				wantLines := sortedLines(tt.wantOut)
-				if len(gotLines) != len(wantLines) {
-					t.Errorf("Sync() gotOut = %q, want %q", gotOut, tt.wantOut)
-				} else {
-					for i := range gotLines {
-						if gotLines[i] != wantLines[i] {
-							t.Errorf("Sync() gotOut = %q, want %q", gotOut, tt.wantOut)
-							break
-						}
-					}
-				}
-			}
-			// Use order-independent comparison for API calls (parallel execution)
-			if !containsAllListCalls(tt.mock.ListLabelsCalls, tt.wantListCall) {
-				t.Errorf("Sync() wantListCall = %v, got %v", tt.wantListCall, tt.mock.ListLabelsCalls)
-			}
-			if !containsAllCalls(tt.mock.CreateLabelCalls, tt.wantCreateCall) {
-				t.Errorf("Sync() wantCreateCall = %v, got %v", tt.wantCreateCall, tt.mock.CreateLabelCalls)
-			}
-			if !containsAllDeleteCalls(tt.mock.DeleteLabelCalls, tt.wantDeleteCall) {
-				t.Errorf("Sync() wantDeleteCall = %v, got %v", tt.wantDeleteCall, tt.mock.DeleteLabelCalls)
-			}
-			if !containsAllCalls(tt.mock.UpdateLabelCalls, tt.wantUpdateCall) {
-				t.Errorf("Sync() wantUpdateCall = %v, got %v", tt.wantUpdateCall, tt.mock.UpdateLabelCalls)
-			}
-		})
-	}
-}
-
-func TestList(t *testing.T) {
-	type args struct {
-		repos []option.Repo
-	}
-	tests := []struct {
-		name    string
-		args    args
-		mock    *mock.MockAPI
-		wantOut string
-		wantErr bool
-		wantListCall []option.Repo
-	}{
 		{
-			name: "single repository with labels",
+			name:   "dry-run with case-only name change",
+			dryrun: true,
 			args: args{
 				repos: []option.Repo{
 					{Owner: "tnagatomi", Repo: "mock-repo"},
 				},
+				labels: []option.Label{
+					{Name: "Bug", Color: "ff0000", Description: "This is a bug"},
+				},
 			},
 			mock: &mock.MockAPI{
 				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
-					return []option.Label{
-						{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
-						{Name: "enhancement", Color: "a2eeef", Description: "New feature or request"},
-						{Name: "documentation", Color: "0075ca", Description: ""},
-					}, nil
+					return []option.Label{{Name: "bug", Color: "d73a4a"}}, nil
 				},
 			},
-			wantOut: `Labels for repository "tnagatomi/mock-repo":
-  bug (#d73a4a) - Something isn't working
-  enhancement (#a2eeef) - New feature or request
-  documentation (#0075ca)
-
-Summary: all operations completed successfully
+			wantOut: `Would rename label "bug" to "Bug" for repository "tnagatomi/mock-repo"
 `,
 			wantErr: false,
 			wantListCall: []option.Repo{
 				{Owner: "tnagatomi", Repo: "mock-repo"},
 			},
+			wantCreateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantDeleteCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{},
 		},
 		{
-			name: "multiple repositories",
+			name:   "unchanged labels are not updated",
+			dryrun: false,
 			args: args{
 				repos: []option.Repo{
-					{Owner: "tnagatomi", Repo: "mock-repo-1"},
-					{Owner: "tnagatomi", Repo: "mock-repo-2"},
+					{Owner: "tnagatomi", Repo: "mock-repo"},
+				},
+				labels: []option.Label{
+					{Name: "bug", Color: "F00", Description: "This is a bug"},
+					{Name: "enhancement", Color: "00ff00", Description: "New description"},
 				},
 			},
 			mock: &mock.MockAPI{
 				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
-					if repo.Repo == "mock-repo-1" {
-						return []option.Label{
-							{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
-						}, nil
-					}
 					return []option.Label{
-						{Name: "enhancement", Color: "a2eeef", Description: ""},
-						{Name: "good first issue", Color: "7057ff", Description: "Good for newcomers"},
+						{Name: "bug", Color: "ff0000", Description: "This is a bug"},
+						{Name: "enhancement", Color: "00ff00", Description: "Old description"},
 					}, nil
 				},
 			},
-			wantOut: `Labels for repository "tnagatomi/mock-repo-1":
-  bug (#d73a4a) - Something isn't working
-Labels for repository "tnagatomi/mock-repo-2":
-  enhancement (#a2eeef)
-  good first issue (#7057ff) - Good for newcomers
+			wantOut: `Updated label "enhancement" for repository "tnagatomi/mock-repo"
 
 Summary: all operations completed successfully
+  tnagatomi/mock-repo: 0 created, 1 updated, 0 deleted, 1 unchanged
 `,
 			wantErr: false,
 			wantListCall: []option.Repo{
-				{Owner: "tnagatomi", Repo: "mock-repo-1"},
-				{Owner: "tnagatomi", Repo: "mock-repo-2"},
+				{Owner: "tnagatomi", Repo: "mock-repo"},
+			},
+			wantCreateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{
+				{Label: option.Label{Name: "enhancement", Color: "00ff00", Description: "New description"}, Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
 			},
+			wantDeleteCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{},
 		},
 		{
-			name: "repository with no labels",
+			name:   "label in use is skipped",
+			dryrun: false,
 			args: args{
 				repos: []option.Repo{
-					{Owner: "tnagatomi", Repo: "empty-repo"},
+					{Owner: "tnagatomi", Repo: "mock-repo"},
+				},
+				labels: []option.Label{
+					{Name: "bug", Color: "ff0000"},
 				},
 			},
 			mock: &mock.MockAPI{
 				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
-					return []option.Label{}, nil
+					return []option.Label{{Name: "bug", Color: "d73a4a"}, {Name: "wontfix"}}, nil
+				},
+				GetLabelUsageFunc: func(repo option.Repo, labelName string) (option.LabelUsage, error) {
+					return option.LabelUsage{Issues: 2, PullRequests: 1}, nil
 				},
 			},
-			wantOut: `Repository "tnagatomi/empty-repo" has no labels
+			wantOut: `Skipped deleting label "wontfix" for repository "tnagatomi/mock-repo": used by 2 issues, 1 pull request
+Updated label "bug" for repository "tnagatomi/mock-repo"
 
 Summary: all operations completed successfully
+  tnagatomi/mock-repo: 0 created, 1 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: false,
 			wantListCall: []option.Repo{
-				{Owner: "tnagatomi", Repo: "empty-repo"},
+				{Owner: "tnagatomi", Repo: "mock-repo"},
+			},
+			wantCreateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{
+				{Label: option.Label{Name: "bug", Color: "ff0000"}, Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
 			},
+			wantDeleteCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{},
 		},
 		{
-			name: "repository not found",
+			name:   "label in use is deleted with delete-used",
+			dryrun: false,
 			args: args{
 				repos: []option.Repo{
-					{Owner: "tnagatomi", Repo: "non-existent-repo"},
+					{Owner: "tnagatomi", Repo: "mock-repo"},
 				},
+				labels:     []option.Label{},
+				deleteUsed: true,
 			},
 			mock: &mock.MockAPI{
 				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
-					return nil, &api.NotFoundError{ResourceType: api.ResourceTypeRepository}
+					return []option.Label{{Name: "wontfix"}}, nil
+				},
+				GetLabelUsageFunc: func(repo option.Repo, labelName string) (option.LabelUsage, error) {
+					return option.LabelUsage{Issues: 2}, nil
 				},
 			},
-			wantOut: `Failed to list labels for repository "tnagatomi/non-existent-repo": repository not found
+			wantOut: `Deleted label "wontfix" for repository "tnagatomi/mock-repo"
 
-Summary: 0 repositories succeeded, 1 failed
+Summary: all operations completed successfully
+  tnagatomi/mock-repo: 0 created, 0 updated, 1 deleted, 0 unchanged
 `,
-			wantErr: true,
+			wantErr: false,
 			wantListCall: []option.Repo{
-				{Owner: "tnagatomi", Repo: "non-existent-repo"},
+				{Owner: "tnagatomi", Repo: "mock-repo"},
+			},
+			wantCreateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantDeleteCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{
+				{Label: "wontfix", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
 			},
 		},
 		{
-			name: "mixed success and failure",
+			name:   "dry-run shows usage counts",
+			dryrun: true,
 			args: args{
 				repos: []option.Repo{
-					{Owner: "tnagatomi", Repo: "repo-1"},
-					{Owner: "tnagatomi", Repo: "repo-2"},
-					{Owner: "tnagatomi", Repo: "repo-3"},
+					{Owner: "tnagatomi", Repo: "mock-repo"},
 				},
+				labels: []option.Label{},
 			},
 			mock: &mock.MockAPI{
 				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
-					if repo.Repo == "repo-2" {
-						return nil, &api.ForbiddenError{}
+					return []option.Label{{Name: "wontfix"}, {Name: "question"}}, nil
+				},
+				GetLabelUsageFunc: func(repo option.Repo, labelName string) (option.LabelUsage, error) {
+					if labelName == "wontfix" {
+						return option.LabelUsage{Discussions: 1}, nil
 					}
-					return []option.Label{
-						{Name: "bug", Color: "d73a4a", Description: ""},
+					return option.LabelUsage{}, nil
+				},
+			},
+			wantOut: `Would skip deleting label "wontfix" for repository "tnagatomi/mock-repo": used by 1 discussion
+Would delete label "question" for repository "tnagatomi/mock-repo"
+`,
+			wantErr: false,
+			wantListCall: []option.Repo{
+				{Owner: "tnagatomi", Repo: "mock-repo"},
+			},
+			wantCreateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantDeleteCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{},
+		},
+		{
+			name:   "dry-run",
+			dryrun: true,
+			args: args{
+				repos: []option.Repo{
+					{Owner: "tnagatomi", Repo: "mock-repo-1"},
+					{Owner: "tnagatomi", Repo: "mock-repo-2"},
+				},
+				labels: []option.Label{
+					{Name: "bug", Color: "ff0000", Description: "This is a bug"},
+					{Name: "enhancement", Color: "00ff00", Description: "This is an enhancement"},
+				},
+			},
+			mock: &mock.MockAPI{
+				CreateLabelFunc: func(label option.Label, repo option.Repo) error {
+					return nil
+				},
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					if repo.Repo == "mock-repo-1" {
+						return []option.Label{
+						{Name: "bug"},
+						{Name: "enhancement"},
+						{Name: "question"},
 					}, nil
+					}
+					return []option.Label{}, nil
+				},
+				DeleteLabelFunc: func(label string, repo option.Repo) error {
+					return nil
 				},
 			},
-			wantOut: `Labels for repository "tnagatomi/repo-1":
-  bug (#d73a4a)
-Failed to list labels for repository "tnagatomi/repo-2": forbidden
-Labels for repository "tnagatomi/repo-3":
-  bug (#d73a4a)
-
-Summary: 2 repositories succeeded, 1 failed
+			wantOut: `Would delete label "question" for repository "tnagatomi/mock-repo-1"
+Would update label "bug" for repository "tnagatomi/mock-repo-1"
+Would update label "enhancement" for repository "tnagatomi/mock-repo-1"
+Would create label "bug" for repository "tnagatomi/mock-repo-2"
+Would create label "enhancement" for repository "tnagatomi/mock-repo-2"
 `,
-			wantErr: true,
+			wantErr: false,
 			wantListCall: []option.Repo{
-				{Owner: "tnagatomi", Repo: "repo-1"},
-				{Owner: "tnagatomi", Repo: "repo-2"},
-				{Owner: "tnagatomi", Repo: "repo-3"},
+				{Owner: "tnagatomi", Repo: "mock-repo-1"},
+				{Owner: "tnagatomi", Repo: "mock-repo-2"},
 			},
+			wantCreateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+			wantDeleteCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{},
 		},
 	}
 
@@ -1331,45 +1506,61 @@ Summary: 2 repositories succeeded, 1 failed
 		t.Run(tt.name, func(t *testing.T) {
 			e := &Executor{
 				api:    tt.mock,
-				dryRun: false,
+				dryRun: tt.dryrun,
 			}
 			out := &bytes.Buffer{}
-			err := e.List(out, tt.args.repos)
+			err := e.Sync(context.Background(), out, tt.args.repos, tt.args.labels, SyncModeFull, tt.args.deleteUsed)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Sync() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if gotOut := stripProgress(out.String()); gotOut != tt.wantOut {
-				t.Errorf("List() gotOut = %q, want %q", gotOut, tt.wantOut)
+			gotOut := stripProgress(out.String())
+			// For parallel execution, compare output lines in sorted order
+			if sortedLines(gotOut) != nil && sortedLines(tt.wantOut) != nil {
+				gotLines := sortedLines(gotOut)
+				wantLines := sortedLines(tt.wantOut)
+				if len(gotLines) != len(wantLines) {
+					t.Errorf("Sync() gotOut = %q, want %q", gotOut, tt.wantOut)
+				} else {
+					for i := range gotLines {
+						if gotLines[i] != wantLines[i] {
+							t.Errorf("Sync() gotOut = %q, want %q", gotOut, tt.wantOut)
+							break
+						}
+					}
+				}
 			}
 			// Use order-independent comparison for API calls (parallel execution)
 			if !containsAllListCalls(tt.mock.ListLabelsCalls, tt.wantListCall) {
-				t.Errorf("List() wantListCall = %v, got %v", tt.wantListCall, tt.mock.ListLabelsCalls)
+				t.Errorf("Sync() wantListCall = %v, got %v", tt.wantListCall, tt.mock.ListLabelsCalls)
+			}
+			if !containsAllCalls(tt.mock.CreateLabelCalls, tt.wantCreateCall) {
+				t.Errorf("Sync() wantCreateCall = %v, got %v", tt.wantCreateCall, tt.mock.CreateLabelCalls)
+			}
+			if !containsAllDeleteCalls(tt.mock.DeleteLabelCalls, tt.wantDeleteCall) {
+				t.Errorf("Sync() wantDeleteCall = %v, got %v", tt.wantDeleteCall, tt.mock.DeleteLabelCalls)
+			}
+			if !containsAllCalls(tt.mock.UpdateLabelCalls, tt.wantUpdateCall) {
+				t.Errorf("Sync() wantUpdateCall = %v, got %v", tt.wantUpdateCall, tt.mock.UpdateLabelCalls)
 			}
 		})
 	}
 }
 
-func TestEmpty(t *testing.T) {
+func TestList(t *testing.T) {
 	type args struct {
 		repos []option.Repo
 	}
 	tests := []struct {
 		name    string
-		dryrun  bool
 		args    args
 		mock    *mock.MockAPI
 		wantOut string
 		wantErr bool
 		wantListCall []option.Repo
-		wantDeleteCall []struct {
-			Label string
-			Repo  option.Repo
-		}	
 	}{
 		{
-			name:   "single repository",
-			dryrun: false,
+			name: "single repository with labels",
 			args: args{
 				repos: []option.Repo{
 					{Owner: "tnagatomi", Repo: "mock-repo"},
@@ -1378,18 +1569,16 @@ func TestEmpty(t *testing.T) {
 			mock: &mock.MockAPI{
 				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
 					return []option.Label{
-						{Name: "bug"},
-						{Name: "enhancement"},
-						{Name: "question"},
+						{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+						{Name: "enhancement", Color: "a2eeef", Description: "New feature or request"},
+						{Name: "documentation", Color: "0075ca", Description: ""},
 					}, nil
 				},
-				DeleteLabelFunc: func(label string, repo option.Repo) error {
-					return nil
-				},
 			},
-			wantOut: `Deleted label "bug" for repository "tnagatomi/mock-repo"
-Deleted label "enhancement" for repository "tnagatomi/mock-repo"
-Deleted label "question" for repository "tnagatomi/mock-repo"
+			wantOut: `Labels for repository "tnagatomi/mock-repo":
+  bug (#d73a4a) - Something isn't working
+  enhancement (#a2eeef) - New feature or request
+  documentation (#0075ca)
 
 Summary: all operations completed successfully
 `,
@@ -1397,18 +1586,9 @@ Summary: all operations completed successfully
 			wantListCall: []option.Repo{
 				{Owner: "tnagatomi", Repo: "mock-repo"},
 			},
-			wantDeleteCall: []struct {
-				Label string
-				Repo  option.Repo
-			}{
-				{Label: "bug", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
-				{Label: "enhancement", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
-				{Label: "question", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
-			},
 		},
 		{
-			name:   "multiple repository",
-			dryrun: false,
+			name: "multiple repositories",
 			args: args{
 				repos: []option.Repo{
 					{Owner: "tnagatomi", Repo: "mock-repo-1"},
@@ -1417,29 +1597,22 @@ Summary: all operations completed successfully
 			},
 			mock: &mock.MockAPI{
 				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
-					if repo.Owner == "tnagatomi" && repo.Repo == "mock-repo-1" {
-						return []option.Label{
-						{Name: "bug"},
-						{Name: "enhancement"},
-						{Name: "question"},
-					}, nil
-					} else if repo.Owner == "tnagatomi" && repo.Repo == "mock-repo-2" {
+					if repo.Repo == "mock-repo-1" {
 						return []option.Label{
-						{Name: "invalid"},
-						{Name: "help wanted"},
-					}, nil
+							{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+						}, nil
 					}
-					return nil, fmt.Errorf("unexpected repository: %v", repo)
-				},
-				DeleteLabelFunc: func(label string, repo option.Repo) error {
-					return nil
+					return []option.Label{
+						{Name: "enhancement", Color: "a2eeef", Description: ""},
+						{Name: "good first issue", Color: "7057ff", Description: "Good for newcomers"},
+					}, nil
 				},
 			},
-			wantOut: `Deleted label "bug" for repository "tnagatomi/mock-repo-1"
-Deleted label "enhancement" for repository "tnagatomi/mock-repo-1"
-Deleted label "question" for repository "tnagatomi/mock-repo-1"
-Deleted label "invalid" for repository "tnagatomi/mock-repo-2"
-Deleted label "help wanted" for repository "tnagatomi/mock-repo-2"
+			wantOut: `Labels for repository "tnagatomi/mock-repo-1":
+  bug (#d73a4a) - Something isn't working
+Labels for repository "tnagatomi/mock-repo-2":
+  enhancement (#a2eeef)
+  good first issue (#7057ff) - Good for newcomers
 
 Summary: all operations completed successfully
 `,
@@ -1448,20 +1621,30 @@ Summary: all operations completed successfully
 				{Owner: "tnagatomi", Repo: "mock-repo-1"},
 				{Owner: "tnagatomi", Repo: "mock-repo-2"},
 			},
-			wantDeleteCall: []struct {
-				Label string
-				Repo  option.Repo
-			}{
-				{Label: "bug", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo-1"}},
-				{Label: "enhancement", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo-1"}},
-				{Label: "question", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo-1"}},
-				{Label: "invalid", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo-2"}},
-				{Label: "help wanted", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo-2"}},
+		},
+		{
+			name: "repository with no labels",
+			args: args{
+				repos: []option.Repo{
+					{Owner: "tnagatomi", Repo: "empty-repo"},
+				},
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return []option.Label{}, nil
+				},
+			},
+			wantOut: `Repository "tnagatomi/empty-repo" has no labels
+
+Summary: all operations completed successfully
+`,
+			wantErr: false,
+			wantListCall: []option.Repo{
+				{Owner: "tnagatomi", Repo: "empty-repo"},
 			},
 		},
 		{
-			name:   "repository not found on list",
-			dryrun: false,
+			name: "repository not found",
 			args: args{
 				repos: []option.Repo{
 					{Owner: "tnagatomi", Repo: "non-existent-repo"},
@@ -1480,17 +1663,211 @@ Summary: 0 repositories succeeded, 1 failed
 			wantListCall: []option.Repo{
 				{Owner: "tnagatomi", Repo: "non-existent-repo"},
 			},
-			wantDeleteCall: []struct {
-				Label string
-				Repo  option.Repo
-			}{},
 		},
 		{
-			name:   "permission error on delete",
-			dryrun: false,
+			name: "mixed success and failure",
 			args: args{
 				repos: []option.Repo{
-					{Owner: "tnagatomi", Repo: "private-repo"},
+					{Owner: "tnagatomi", Repo: "repo-1"},
+					{Owner: "tnagatomi", Repo: "repo-2"},
+					{Owner: "tnagatomi", Repo: "repo-3"},
+				},
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					if repo.Repo == "repo-2" {
+						return nil, &api.ForbiddenError{}
+					}
+					return []option.Label{
+						{Name: "bug", Color: "d73a4a", Description: ""},
+					}, nil
+				},
+			},
+			wantOut: `Labels for repository "tnagatomi/repo-1":
+  bug (#d73a4a)
+Failed to list labels for repository "tnagatomi/repo-2": forbidden
+Labels for repository "tnagatomi/repo-3":
+  bug (#d73a4a)
+
+Summary: 2 repositories succeeded, 1 failed
+`,
+			wantErr: true,
+			wantListCall: []option.Repo{
+				{Owner: "tnagatomi", Repo: "repo-1"},
+				{Owner: "tnagatomi", Repo: "repo-2"},
+				{Owner: "tnagatomi", Repo: "repo-3"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Executor{
+				api:    tt.mock,
+				dryRun: false,
+			}
+			out := &bytes.Buffer{}
+			err := e.List(context.Background(), out, tt.args.repos)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotOut := stripProgress(out.String()); gotOut != tt.wantOut {
+				t.Errorf("List() gotOut = %q, want %q", gotOut, tt.wantOut)
+			}
+			// Use order-independent comparison for API calls (parallel execution)
+			if !containsAllListCalls(tt.mock.ListLabelsCalls, tt.wantListCall) {
+				t.Errorf("List() wantListCall = %v, got %v", tt.wantListCall, tt.mock.ListLabelsCalls)
+			}
+		})
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	type args struct {
+		repos []option.Repo
+	}
+	tests := []struct {
+		name    string
+		dryrun  bool
+		args    args
+		mock    *mock.MockAPI
+		wantOut string
+		wantErr bool
+		wantListCall []option.Repo
+		wantDeleteCall []struct {
+			Label string
+			Repo  option.Repo
+		}	
+	}{
+		{
+			name:   "single repository",
+			dryrun: false,
+			args: args{
+				repos: []option.Repo{
+					{Owner: "tnagatomi", Repo: "mock-repo"},
+				},
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return []option.Label{
+						{Name: "bug"},
+						{Name: "enhancement"},
+						{Name: "question"},
+					}, nil
+				},
+				DeleteLabelFunc: func(label string, repo option.Repo) error {
+					return nil
+				},
+			},
+			wantOut: `Deleted label "bug" for repository "tnagatomi/mock-repo"
+Deleted label "enhancement" for repository "tnagatomi/mock-repo"
+Deleted label "question" for repository "tnagatomi/mock-repo"
+
+Summary: all operations completed successfully
+  tnagatomi/mock-repo: 0 created, 0 updated, 3 deleted, 0 unchanged
+`,
+			wantErr: false,
+			wantListCall: []option.Repo{
+				{Owner: "tnagatomi", Repo: "mock-repo"},
+			},
+			wantDeleteCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{
+				{Label: "bug", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
+				{Label: "enhancement", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
+				{Label: "question", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}},
+			},
+		},
+		{
+			name:   "multiple repository",
+			dryrun: false,
+			args: args{
+				repos: []option.Repo{
+					{Owner: "tnagatomi", Repo: "mock-repo-1"},
+					{Owner: "tnagatomi", Repo: "mock-repo-2"},
+				},
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					if repo.Owner == "tnagatomi" && repo.Repo == "mock-repo-1" {
+						return []option.Label{
+						{Name: "bug"},
+						{Name: "enhancement"},
+						{Name: "question"},
+					}, nil
+					} else if repo.Owner == "tnagatomi" && repo.Repo == "mock-repo-2" {
+						return []option.Label{
+						{Name: "invalid"},
+						{Name: "help wanted"},
+					}, nil
+					}
+					return nil, fmt.Errorf("unexpected repository: %v", repo)
+				},
+				DeleteLabelFunc: func(label string, repo option.Repo) error {
+					return nil
+				},
+			},
+			wantOut: `Deleted label "bug" for repository "tnagatomi/mock-repo-1"
+Deleted label "enhancement" for repository "tnagatomi/mock-repo-1"
+Deleted label "question" for repository "tnagatomi/mock-repo-1"
+Deleted label "invalid" for repository "tnagatomi/mock-repo-2"
+Deleted label "help wanted" for repository "tnagatomi/mock-repo-2"
+
+Summary: all operations completed successfully
+  tnagatomi/mock-repo-1: 0 created, 0 updated, 3 deleted, 0 unchanged
+  tnagatomi/mock-repo-2: 0 created, 0 updated, 2 deleted, 0 unchanged
+`,
+			wantErr: false,
+			wantListCall: []option.Repo{
+				{Owner: "tnagatomi", Repo: "mock-repo-1"},
+				{Owner: "tnagatomi", Repo: "mock-repo-2"},
+			},
+			wantDeleteCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{
+				{Label: "bug", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo-1"}},
+				{Label: "enhancement", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo-1"}},
+				{Label: "question", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo-1"}},
+				{Label: "invalid", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo-2"}},
+				{Label: "help wanted", Repo: option.Repo{Owner: "tnagatomi", Repo: "mock-repo-2"}},
+			},
+		},
+		{
+			name:   "repository not found on list",
+			dryrun: false,
+			args: args{
+				repos: []option.Repo{
+					{Owner: "tnagatomi", Repo: "non-existent-repo"},
+				},
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return nil, &api.NotFoundError{ResourceType: api.ResourceTypeRepository}
+				},
+			},
+			wantOut: `Failed to list labels for repository "tnagatomi/non-existent-repo": repository not found
+
+Summary: 0 repositories succeeded, 1 failed
+  tnagatomi/non-existent-repo: 0 created, 0 updated, 0 deleted, 0 unchanged
+`,
+			wantErr: true,
+			wantListCall: []option.Repo{
+				{Owner: "tnagatomi", Repo: "non-existent-repo"},
+			},
+			wantDeleteCall: []struct {
+				Label string
+				Repo  option.Repo
+			}{},
+		},
+		{
+			name:   "permission error on delete",
+			dryrun: false,
+			args: args{
+				repos: []option.Repo{
+					{Owner: "tnagatomi", Repo: "private-repo"},
 				},
 			},
 			mock: &mock.MockAPI{
@@ -1508,6 +1885,7 @@ Summary: 0 repositories succeeded, 1 failed
 Failed to delete label "enhancement" for repository "tnagatomi/private-repo": forbidden
 
 Summary: 0 repositories succeeded, 1 failed
+  tnagatomi/private-repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantListCall: []option.Repo{
@@ -1547,6 +1925,9 @@ Failed to list labels for repository "tnagatomi/repo-2": repository not found
 Deleted label "bug" for repository "tnagatomi/repo-3"
 
 Summary: 2 repositories succeeded, 1 failed
+  tnagatomi/repo-1: 0 created, 0 updated, 1 deleted, 0 unchanged
+  tnagatomi/repo-2: 0 created, 0 updated, 0 deleted, 0 unchanged
+  tnagatomi/repo-3: 0 created, 0 updated, 1 deleted, 0 unchanged
 `,
 			wantErr: true,
 			wantListCall: []option.Repo{
@@ -1604,7 +1985,7 @@ Would delete label "question" for repository "tnagatomi/mock-repo"
 				dryRun: tt.dryrun,
 			}
 			out := &bytes.Buffer{}
-			err := e.Empty(out, tt.args.repos)
+			err := e.Empty(context.Background(), out, tt.args.repos)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Empty() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1685,6 +2066,7 @@ Removed label "old-label" from Issue #1 in repository "owner/repo"
 Deleted label "old-label" from repository "owner/repo"
 
 Summary: all operations completed successfully
+  owner/repo: 0 created, 0 updated, 1 deleted, 0 unchanged
 `,
 			wantErr: false,
 		},
@@ -1713,6 +2095,7 @@ Summary: all operations completed successfully
 			wantOut: `Deleted label "old-label" from repository "owner/repo"
 
 Summary: all operations completed successfully
+  owner/repo: 0 created, 0 updated, 1 deleted, 0 unchanged
 `,
 			wantErr: false,
 		},
@@ -1735,6 +2118,7 @@ Summary: all operations completed successfully
 			wantOut: `Failed to find source label "nonexistent" in repository "owner/repo": label not found
 
 Summary: 0 repositories succeeded, 1 failed
+  owner/repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 		},
@@ -1757,6 +2141,7 @@ Summary: 0 repositories succeeded, 1 failed
 			wantOut: `Failed to find target label "nonexistent" in repository "owner/repo": label not found
 
 Summary: 0 repositories succeeded, 1 failed
+  owner/repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 		},
@@ -1788,6 +2173,7 @@ Summary: 0 repositories succeeded, 1 failed
 Skipped deleting label "old-label" from repository "owner/repo": 0 items succeeded, 1 items failed
 
 Summary: 0 repositories succeeded, 1 failed
+  owner/repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 		},
@@ -1850,6 +2236,7 @@ Failed to remove label "old-label" from Issue #1 in repository "owner/repo" (tar
 Skipped deleting label "old-label" from repository "owner/repo": 0 items succeeded, 1 items failed
 
 Summary: 0 repositories succeeded, 1 failed
+  owner/repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 		},
@@ -1890,6 +2277,7 @@ Failed to add label "new-label" to Issue #2 in repository "owner/repo": forbidde
 Skipped deleting label "old-label" from repository "owner/repo": 1 items succeeded, 1 items failed
 
 Summary: 0 repositories succeeded, 1 failed
+  owner/repo: 0 created, 0 updated, 0 deleted, 0 unchanged
 `,
 			wantErr: true,
 		},
@@ -1901,7 +2289,7 @@ Summary: 0 repositories succeeded, 1 failed
 				dryRun: tt.dryrun,
 			}
 			out := &bytes.Buffer{}
-			err := e.Merge(out, tt.args.repos, tt.args.fromLabel, tt.args.toLabel)
+			err := e.Merge(context.Background(), out, tt.args.repos, tt.args.fromLabel, tt.args.toLabel)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Merge() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1925,3 +2313,764 @@ Summary: 0 repositories succeeded, 1 failed
 		})
 	}
 }
+
+func TestRename(t *testing.T) {
+	type args struct {
+		repos       []option.Repo
+		fromLabel   string
+		toLabel     string
+		color       string
+		description string
+	}
+	tests := []struct {
+		name           string
+		dryrun         bool
+		args           args
+		mock           *mock.MockAPI
+		wantOut        string
+		wantErr        bool
+		wantUpdateCall []struct {
+			Label option.Label
+			Repo  option.Repo
+		}
+	}{
+		{
+			name:   "rename in place keeps color and description",
+			dryrun: false,
+			args: args{
+				repos:     []option.Repo{{Owner: "owner", Repo: "repo"}},
+				fromLabel: "type/bug",
+				toLabel:   "kind: bug",
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return []option.Label{{Name: "type/bug", Color: "d73a4a", Description: "Something isn't working"}}, nil
+				},
+			},
+			wantOut: `Renamed label "type/bug" to "kind: bug" for repository "owner/repo"
+
+Summary: all operations completed successfully
+  owner/repo: 0 created, 1 updated, 0 deleted, 0 unchanged
+`,
+			wantErr: false,
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{
+				{Label: option.Label{Name: "type/bug", Color: "d73a4a", Description: "Something isn't working", NewName: "kind: bug"}, Repo: option.Repo{Owner: "owner", Repo: "repo"}},
+			},
+		},
+		{
+			name:   "rename with new color and description",
+			dryrun: false,
+			args: args{
+				repos:       []option.Repo{{Owner: "owner", Repo: "repo"}},
+				fromLabel:   "type/bug",
+				toLabel:     "kind: bug",
+				color:       "ff0000",
+				description: "A bug",
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return []option.Label{{Name: "type/bug", Color: "d73a4a", Description: "Something isn't working"}}, nil
+				},
+			},
+			wantOut: `Renamed label "type/bug" to "kind: bug" for repository "owner/repo"
+
+Summary: all operations completed successfully
+  owner/repo: 0 created, 1 updated, 0 deleted, 0 unchanged
+`,
+			wantErr: false,
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{
+				{Label: option.Label{Name: "type/bug", Color: "ff0000", Description: "A bug", NewName: "kind: bug"}, Repo: option.Repo{Owner: "owner", Repo: "repo"}},
+			},
+		},
+		{
+			name:   "case-only rename",
+			dryrun: false,
+			args: args{
+				repos:     []option.Repo{{Owner: "owner", Repo: "repo"}},
+				fromLabel: "bug",
+				toLabel:   "Bug",
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return []option.Label{{Name: "bug", Color: "d73a4a"}}, nil
+				},
+			},
+			wantOut: `Renamed label "bug" to "Bug" for repository "owner/repo"
+
+Summary: all operations completed successfully
+  owner/repo: 0 created, 1 updated, 0 deleted, 0 unchanged
+`,
+			wantErr: false,
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{
+				{Label: option.Label{Name: "bug", Color: "d73a4a", NewName: "Bug"}, Repo: option.Repo{Owner: "owner", Repo: "repo"}},
+			},
+		},
+		{
+			name:   "existing target falls back to merge",
+			dryrun: false,
+			args: args{
+				repos:     []option.Repo{{Owner: "owner", Repo: "repo"}},
+				fromLabel: "type/bug",
+				toLabel:   "kind: bug",
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return []option.Label{{Name: "type/bug"}, {Name: "kind: bug"}}, nil
+				},
+				GetLabelIDFunc: func(repo option.Repo, labelName string) (option.GraphQLID, error) {
+					return option.GraphQLID("LA_" + labelName), nil
+				},
+				SearchLabelablesFunc: func(repo option.Repo, labelName string) ([]option.Labelable, error) {
+					return []option.Labelable{
+						{ID: "I_1", Number: 1, Title: "Issue 1", Type: option.LabelableTypeIssue},
+					}, nil
+				},
+			},
+			wantOut: `Added label "kind: bug" to Issue #1 in repository "owner/repo"
+Removed label "type/bug" from Issue #1 in repository "owner/repo"
+Deleted label "type/bug" from repository "owner/repo"
+
+Summary: all operations completed successfully
+  owner/repo: 0 created, 0 updated, 1 deleted, 0 unchanged
+`,
+			wantErr: false,
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+		},
+		{
+			name:   "source label not found",
+			dryrun: false,
+			args: args{
+				repos:     []option.Repo{{Owner: "owner", Repo: "repo"}},
+				fromLabel: "nonexistent",
+				toLabel:   "kind: bug",
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return []option.Label{{Name: "bug"}}, nil
+				},
+			},
+			wantOut: `Failed to find source label "nonexistent" in repository "owner/repo": label not found
+
+Summary: 0 repositories succeeded, 1 failed
+  owner/repo: 0 created, 0 updated, 0 deleted, 0 unchanged
+`,
+			wantErr: true,
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+		},
+		{
+			name:   "dry-run",
+			dryrun: true,
+			args: args{
+				repos:     []option.Repo{{Owner: "owner", Repo: "repo"}},
+				fromLabel: "type/bug",
+				toLabel:   "kind: bug",
+			},
+			mock: &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return []option.Label{{Name: "type/bug"}}, nil
+				},
+			},
+			wantOut: `Would rename label "type/bug" to "kind: bug" for repository "owner/repo"
+`,
+			wantErr: false,
+			wantUpdateCall: []struct {
+				Label option.Label
+				Repo  option.Repo
+			}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Executor{
+				api:    tt.mock,
+				dryRun: tt.dryrun,
+			}
+			out := &bytes.Buffer{}
+			err := e.Rename(context.Background(), out, tt.args.repos, tt.args.fromLabel, tt.args.toLabel, tt.args.color, tt.args.description)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Rename() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			gotOut := stripProgress(out.String())
+			if diff := cmp.Diff(sortedLines(tt.wantOut), sortedLines(gotOut)); diff != "" {
+				t.Errorf("Rename() output mismatch (-want +got):\n%s", diff)
+			}
+			if !containsAllCalls(tt.mock.UpdateLabelCalls, tt.wantUpdateCall) {
+				t.Errorf("Rename() wantUpdateCall = %v, got %v", tt.wantUpdateCall, tt.mock.UpdateLabelCalls)
+			}
+		})
+	}
+}
+
+func TestResolveRepos(t *testing.T) {
+	orgRepos := []option.Repository{
+		{Repo: option.Repo{Owner: "org", Repo: "active"}, HasIssuesEnabled: true, Visibility: "public", Language: "Go", Topics: []string{"backend", "api"}},
+		{Repo: option.Repo{Owner: "org", Repo: "archived"}, IsArchived: true, HasIssuesEnabled: true, Visibility: "public", Language: "Go", Topics: []string{"backend"}},
+		{Repo: option.Repo{Owner: "org", Repo: "fork"}, IsFork: true, HasIssuesEnabled: true, Visibility: "public"},
+		{Repo: option.Repo{Owner: "org", Repo: "template"}, IsTemplate: true, HasIssuesEnabled: true, Visibility: "private"},
+		{Repo: option.Repo{Owner: "org", Repo: "no-issues"}, Visibility: "internal"},
+	}
+
+	tests := []struct {
+		name    string
+		repos   []option.Repo
+		query    string
+		filter   option.RepoFilter
+		excludes     option.RepoPatterns
+		want         []option.Repo
+		wantExcluded []option.Repo
+		wantQueries  []string
+		wantErr      string
+	}{
+		{
+			name:  "explicit repositories are kept as is",
+			repos: []option.Repo{{Owner: "org", Repo: "archived"}, {Owner: "other", Repo: "repo"}},
+			want:  []option.Repo{{Owner: "org", Repo: "archived"}, {Owner: "other", Repo: "repo"}},
+		},
+		{
+			name:  "wildcard without filter",
+			repos: []option.Repo{{Owner: "org", Repo: "*"}},
+			want: []option.Repo{
+				{Owner: "org", Repo: "active"},
+				{Owner: "org", Repo: "archived"},
+				{Owner: "org", Repo: "fork"},
+				{Owner: "org", Repo: "template"},
+				{Owner: "org", Repo: "no-issues"},
+			},
+		},
+		{
+			name:   "wildcard with filters",
+			repos:  []option.Repo{{Owner: "org", Repo: "*"}},
+			filter: option.RepoFilter{SkipArchived: true, SkipForks: true, SkipIssuesDisabled: true, SkipVisibilities: []string{"private"}},
+			want:   []option.Repo{{Owner: "org", Repo: "active"}},
+		},
+		{
+			name:   "duplicates are removed",
+			repos:  []option.Repo{{Owner: "org", Repo: "fork"}, {Owner: "org", Repo: "*"}},
+			filter: option.RepoFilter{SkipTemplates: true, SkipIssuesDisabled: true},
+			want: []option.Repo{
+				{Owner: "org", Repo: "fork"},
+				{Owner: "org", Repo: "active"},
+				{Owner: "org", Repo: "archived"},
+			},
+		},
+		{
+			name:   "duplicates differing in case are removed",
+			repos:  []option.Repo{{Owner: "Org", Repo: "Fork"}, {Owner: "org", Repo: "*"}},
+			filter: option.RepoFilter{SkipTemplates: true, SkipIssuesDisabled: true},
+			want: []option.Repo{
+				{Owner: "Org", Repo: "Fork"},
+				{Owner: "org", Repo: "active"},
+				{Owner: "org", Repo: "archived"},
+			},
+		},
+		{
+			name:   "wildcard with topic and language",
+			repos:  []option.Repo{{Owner: "org", Repo: "*"}},
+			filter: option.RepoFilter{Topics: []string{"Backend", "api"}, Language: "go"},
+			want:   []option.Repo{{Owner: "org", Repo: "active"}},
+			wantQueries: []string{"user:org topic:Backend topic:api language:go fork:true"},
+		},
+		{
+			name:        "wildcard with topic skipping archived and forks",
+			repos:       []option.Repo{{Owner: "org", Repo: "*"}},
+			filter:      option.RepoFilter{Topics: []string{"backend"}, Language: "Jupyter Notebook", SkipArchived: true, SkipForks: true},
+			wantQueries: []string{`user:org topic:backend language:"Jupyter Notebook" archived:false`},
+			wantErr:     "no repositories matched",
+		},
+		{
+			name:   "search query",
+			repos:  []option.Repo{{Owner: "other", Repo: "repo"}},
+			query:  "org:acme topic:backend",
+			filter: option.RepoFilter{SkipArchived: true},
+			want:   []option.Repo{{Owner: "other", Repo: "repo"}, {Owner: "acme", Repo: "api"}},
+			wantQueries: []string{"org:acme topic:backend"},
+		},
+		{
+			name:        "search query with topic",
+			query:       "org:acme",
+			filter:      option.RepoFilter{Topics: []string{"backend"}},
+			want:        []option.Repo{{Owner: "acme", Repo: "api"}, {Owner: "acme", Repo: "old-api"}},
+			wantQueries: []string{"org:acme topic:backend"},
+		},
+		{
+			name:     "excluded repositories",
+			repos:    []option.Repo{{Owner: "other", Repo: "repo"}, {Owner: "org", Repo: "*"}},
+			filter:   option.RepoFilter{SkipArchived: true},
+			excludes: option.RepoPatterns{"Other/Repo", "org/fork", "org/template"},
+			want:     []option.Repo{{Owner: "org", Repo: "active"}, {Owner: "org", Repo: "no-issues"}},
+			wantExcluded: []option.Repo{
+				{Owner: "other", Repo: "repo"},
+				{Owner: "org", Repo: "fork"},
+				{Owner: "org", Repo: "template"},
+			},
+		},
+		{
+			name:         "glob exclusions",
+			repos:        []option.Repo{{Owner: "other", Repo: "repo"}, {Owner: "org", Repo: "*"}},
+			excludes:     option.RepoPatterns{"org/*t*", "*/no-*"},
+			want:         []option.Repo{{Owner: "other", Repo: "repo"}, {Owner: "org", Repo: "archived"}, {Owner: "org", Repo: "fork"}},
+			wantExcluded: []option.Repo{{Owner: "org", Repo: "active"}, {Owner: "org", Repo: "template"}, {Owner: "org", Repo: "no-issues"}},
+		},
+		{
+			name:     "everything excluded",
+			repos:    []option.Repo{{Owner: "other", Repo: "repo"}},
+			excludes: option.RepoPatterns{"other/*"},
+			wantErr:  "no repositories matched: all 1 excluded",
+		},
+		{
+			name:    "owner not found",
+			repos:   []option.Repo{{Owner: "missing", Repo: "*"}},
+			wantErr: `failed to list repositories of "missing": owner not found`,
+		},
+		{
+			name:    "nothing matched",
+			repos:   []option.Repo{{Owner: "org", Repo: "*"}},
+			filter:  option.RepoFilter{SkipVisibilities: []string{"public", "private", "internal"}},
+			wantErr: "no repositories matched",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mock.MockAPI{
+				ListRepositoriesFunc: func(owner string) ([]option.Repository, error) {
+					if owner != "org" {
+						return nil, &api.NotFoundError{ResourceType: api.ResourceTypeOwner}
+					}
+					return orgRepos, nil
+				},
+				SearchRepositoriesFunc: func(query string) ([]option.Repository, error) {
+					if strings.HasPrefix(query, "user:org ") {
+						return orgRepos, nil
+					}
+					return []option.Repository{
+						{Repo: option.Repo{Owner: "acme", Repo: "api"}, Topics: []string{"backend"}},
+						{Repo: option.Repo{Owner: "acme", Repo: "old-api"}, IsArchived: true, Topics: []string{"backend"}},
+					}, nil
+				},
+			}
+			e := &Executor{api: m, repoFilter: tt.filter, repoQuery: tt.query, repoExcludes: tt.excludes}
+
+			got, excluded, err := e.resolveRepos(context.Background(), tt.repos)
+			var queries []string
+			for _, call := range m.SearchRepositoriesCalls {
+				queries = append(queries, call.Query)
+			}
+			if diff := cmp.Diff(tt.wantQueries, queries); diff != "" {
+				t.Errorf("resolveRepos() search queries mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("resolveRepos() error = %v, wantErr %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveRepos() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("resolveRepos() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantExcluded, excluded); diff != "" {
+				t.Errorf("resolveRepos() excluded mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRateLimitDelay_PerHost(t *testing.T) {
+	dotcom := &mock.MockAPI{}
+	ghes := &mock.MockAPI{
+		RateLimitDelayFunc: func() time.Duration { return time.Minute },
+	}
+	e := &Executor{
+		api:      dotcom,
+		host:     "github.com",
+		hostAPIs: map[string]api.APIClient{"ghe.example.com": ghes},
+	}
+
+	// The exhausted rate limit of one host does not hold back the others
+	tests := []struct {
+		repo option.Repo
+		want time.Duration
+	}{
+		{repo: option.Repo{Owner: "owner", Repo: "repo"}, want: 0},
+		{repo: option.Repo{Host: "ghe.example.com", Owner: "org", Repo: "repo"}, want: time.Minute},
+	}
+	for _, tt := range tests {
+		if got := e.rateLimitDelay(e.ownerKey(tt.repo)); got != tt.want {
+			t.Errorf("rateLimitDelay() for %q = %v, want %v", tt.repo, got, tt.want)
+		}
+	}
+}
+
+func TestMultipleHosts(t *testing.T) {
+	newMock := func() *mock.MockAPI {
+		return &mock.MockAPI{
+			CreateLabelFunc: func(label option.Label, repo option.Repo) error {
+				return nil
+			},
+			ListRepositoriesFunc: func(owner string) ([]option.Repository, error) {
+				return []option.Repository{{Repo: option.Repo{Owner: owner, Repo: "app"}}}, nil
+			},
+		}
+	}
+	dotcom := newMock()
+	ghes := newMock()
+
+	var connected []string
+	e := &Executor{
+		api:  dotcom,
+		host: "github.com",
+		newAPI: func(host string) (api.APIClient, error) {
+			connected = append(connected, host)
+			if host != "ghe.example.com" {
+				return nil, fmt.Errorf("authentication token not found for host %s", host)
+			}
+			return ghes, nil
+		},
+	}
+
+	repos := []option.Repo{
+		{Host: "github.com", Owner: "owner", Repo: "repo"},
+		{Host: "ghe.example.com", Owner: "org", Repo: "*"},
+		{Host: "ghe.example.com", Owner: "org", Repo: "lib"},
+	}
+	labels := []option.Label{{Name: "bug", Color: "d73a4a"}}
+
+	out := &bytes.Buffer{}
+	if err := e.Create(context.Background(), out, repos, labels, false); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"ghe.example.com"}, connected); diff != "" {
+		t.Errorf("Create() connected hosts mismatch (-want +got):\n%s", diff)
+	}
+	wantDotcom := []struct {
+		Label option.Label
+		Repo  option.Repo
+	}{
+		{Label: labels[0], Repo: option.Repo{Owner: "owner", Repo: "repo"}},
+	}
+	if !containsAllCalls(dotcom.CreateLabelCalls, wantDotcom) {
+		t.Errorf("Create() github.com calls = %v, want %v", dotcom.CreateLabelCalls, wantDotcom)
+	}
+	wantGHES := []struct {
+		Label option.Label
+		Repo  option.Repo
+	}{
+		{Label: labels[0], Repo: option.Repo{Host: "ghe.example.com", Owner: "org", Repo: "app"}},
+		{Label: labels[0], Repo: option.Repo{Host: "ghe.example.com", Owner: "org", Repo: "lib"}},
+	}
+	if !containsAllCalls(ghes.CreateLabelCalls, wantGHES) {
+		t.Errorf("Create() ghe.example.com calls = %v, want %v", ghes.CreateLabelCalls, wantGHES)
+	}
+	if !strings.Contains(out.String(), `Created label "bug" for repository "ghe.example.com/org/app"`) {
+		t.Errorf("Create() output = %q, want the host in repository names", out.String())
+	}
+
+	err := e.Create(context.Background(), out, []option.Repo{{Host: "unknown.example.com", Owner: "org", Repo: "repo"}}, labels, false)
+	if err == nil || !strings.Contains(err.Error(), `failed to initialize API client for host "unknown.example.com"`) {
+		t.Errorf("Create() error = %v, want API client error", err)
+	}
+}
+
+func TestApply_Batches(t *testing.T) {
+	repo := option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}
+	m := &mock.MockAPI{
+		ApplyLabelChangesFunc: func(repo option.Repo, changes []option.LabelChange) []error {
+			errs := make([]error, len(changes))
+			for i, change := range changes {
+				if change.Label.Name == "question" {
+					errs[i] = &api.ForbiddenError{}
+				}
+			}
+			return errs
+		},
+	}
+	e := &Executor{api: m}
+	e.SetBatchSize(2)
+
+	plan := &Plan{
+		Command: CommandSync,
+		Repos: []*RepoPlan{{
+			Repo: repo,
+			Operations: []Operation{
+				{Type: OperationDelete, Before: &option.Label{Name: "question"}},
+				{Type: OperationSkip, Before: &option.Label{Name: "wontfix"}, Usage: &option.LabelUsage{Issues: 1}},
+				{Type: OperationRename, Before: &option.Label{Name: "bug"}, After: &option.Label{Name: "kind/bug", Color: "d73a4a"}},
+				{Type: OperationUnchanged, Before: &option.Label{Name: "docs"}, After: &option.Label{Name: "docs"}},
+				{Type: OperationCreate, After: &option.Label{Name: "enhancement", Color: "a2eeef"}},
+			},
+		}},
+	}
+
+	out := &bytes.Buffer{}
+	err := e.Apply(context.Background(), out, plan)
+	if err == nil {
+		t.Errorf("Apply() error = nil, want the error of the failed change")
+	}
+
+	wantCalls := [][]option.LabelChange{
+		{
+			{Type: option.LabelChangeDelete, Label: option.Label{Name: "question"}},
+			{Type: option.LabelChangeUpdate, Label: option.Label{Name: "bug", NewName: "kind/bug", Color: "d73a4a"}},
+		},
+		{
+			{Type: option.LabelChangeCreate, Label: option.Label{Name: "enhancement", Color: "a2eeef"}},
+		},
+	}
+	var gotCalls [][]option.LabelChange
+	for _, call := range m.ApplyLabelChangesCalls {
+		gotCalls = append(gotCalls, call.Changes)
+	}
+	if diff := cmp.Diff(wantCalls, gotCalls); diff != "" {
+		t.Errorf("Apply() batches mismatch (-want +got):\n%s", diff)
+	}
+
+	wantOutput := `Failed to delete label "question" for repository "tnagatomi/mock-repo": forbidden
+Skipped deleting label "wontfix" for repository "tnagatomi/mock-repo": used by 1 issue
+Renamed label "bug" to "kind/bug" for repository "tnagatomi/mock-repo"
+Created label "enhancement" for repository "tnagatomi/mock-repo"
+`
+	if got := stripProgress(out.String()); !strings.HasPrefix(got, wantOutput) {
+		t.Errorf("Apply() output = %q, want prefix %q", got, wantOutput)
+	}
+}
+
+func TestPrefetchLabels(t *testing.T) {
+	repos := []option.Repo{
+		{Owner: "tnagatomi", Repo: "repo1"},
+		{Owner: "tnagatomi", Repo: "repo2"},
+		{Owner: "tnagatomi", Repo: "repo3"},
+	}
+	newMock := func() *mock.MockAPI {
+		return &mock.MockAPI{
+			ListLabelsOfReposFunc: func(repos []option.Repo) ([][]option.Label, []error) {
+				labels := make([][]option.Label, len(repos))
+				for i := range repos {
+					labels[i] = []option.Label{{Name: "bug", Color: "d73a4a"}}
+				}
+				return labels, make([]error, len(repos))
+			},
+		}
+	}
+	wantBatches := [][]option.Repo{repos[:2], repos[2:]}
+
+	t.Run("list", func(t *testing.T) {
+		m := newMock()
+		e := &Executor{api: m}
+		e.SetBatchSize(2)
+
+		out := &bytes.Buffer{}
+		if err := e.List(context.Background(), out, repos); err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+
+		var gotBatches [][]option.Repo
+		for _, call := range m.ListLabelsOfReposCalls {
+			gotBatches = append(gotBatches, call.Repos)
+		}
+		if diff := cmp.Diff(wantBatches, gotBatches); diff != "" {
+			t.Errorf("List() batches mismatch (-want +got):\n%s", diff)
+		}
+		if len(m.ListLabelsCalls) > 0 {
+			t.Errorf("List() listed labels one repository at a time: %v", m.ListLabelsCalls)
+		}
+		if !strings.Contains(out.String(), "Labels for repository \"tnagatomi/repo3\":\n  bug (#d73a4a)\n") {
+			t.Errorf("List() output = %q, want the labels of every repository", out.String())
+		}
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		m := newMock()
+		e := &Executor{api: m, dryRun: true}
+		e.SetBatchSize(2)
+
+		out := &bytes.Buffer{}
+		if err := e.Sync(context.Background(), out, repos, []option.Label{{Name: "bug", Color: "d73a4a"}}, SyncModeFull, false); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+
+		if len(m.ListLabelsOfReposCalls) != len(wantBatches) || len(m.ListLabelsCalls) > 0 {
+			t.Errorf("Sync() made %d batched and %d single label listings, want %d batched", len(m.ListLabelsOfReposCalls), len(m.ListLabelsCalls), len(wantBatches))
+		}
+		if e.prefetched != nil {
+			t.Errorf("Sync() kept the prefetched labels")
+		}
+	})
+}
+
+func TestApply_MaxMutationsPerMinute(t *testing.T) {
+	repo := option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}
+	m := &mock.MockAPI{}
+	e := &Executor{api: m}
+	e.SetMaxMutationsPerMinute(2)
+	var slept []time.Duration
+	e.mutations.sleep = func(_ context.Context, d time.Duration) { slept = append(slept, d) }
+
+	plan := &Plan{
+		Command: CommandCreate,
+		Repos: []*RepoPlan{{
+			Repo: repo,
+			Operations: []Operation{
+				{Type: OperationCreate, After: &option.Label{Name: "bug", Color: "d73a4a"}},
+				{Type: OperationCreate, After: &option.Label{Name: "docs", Color: "0075ca"}},
+				{Type: OperationCreate, After: &option.Label{Name: "enhancement", Color: "a2eeef"}},
+			},
+		}},
+	}
+
+	if err := e.Apply(context.Background(), &bytes.Buffer{}, plan); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// Batches are no larger than the mutations allowed a minute, and the
+	// second one waits for the minute of the first
+	var sizes []int
+	for _, call := range m.ApplyLabelChangesCalls {
+		sizes = append(sizes, len(call.Changes))
+	}
+	if diff := cmp.Diff([]int{2, 1}, sizes); diff != "" {
+		t.Errorf("Apply() batch sizes mismatch (-want +got):\n%s", diff)
+	}
+	if len(slept) != 1 || slept[0] < 59*time.Second {
+		t.Errorf("slept = %v, want one wait of about a minute", slept)
+	}
+}
+
+func TestApply_Interrupted(t *testing.T) {
+	repo1 := option.Repo{Owner: "tnagatomi", Repo: "repo1"}
+	repo2 := option.Repo{Owner: "tnagatomi", Repo: "repo2"}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Ctrl-C is pressed while the first label of repo1 is created
+	m := &mock.MockAPI{
+		CreateLabelFunc: func(label option.Label, repo option.Repo) error {
+			cancel()
+			return nil
+		},
+	}
+	e := &Executor{api: m}
+	e.SetBatchSize(1)
+	e.SetConcurrency(1)
+
+	ops := []Operation{
+		{Type: OperationCreate, After: &option.Label{Name: "bug", Color: "d73a4a"}},
+		{Type: OperationCreate, After: &option.Label{Name: "docs", Color: "0075ca"}},
+	}
+	plan := &Plan{
+		Command: CommandCreate,
+		Repos: []*RepoPlan{
+			{Repo: repo1, Operations: ops},
+			{Repo: repo2, Operations: ops},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	err := e.Apply(ctx, out, plan)
+	if !errors.Is(err, ErrInterrupted) {
+		t.Errorf("Apply() error = %v, want %v", err, ErrInterrupted)
+	}
+	if len(m.CreateLabelCalls) != 1 {
+		t.Errorf("CreateLabel() calls = %d, want 1", len(m.CreateLabelCalls))
+	}
+
+	want := `Created label "bug" for repository "tnagatomi/repo1"
+Interrupted before finishing the operations for repository "tnagatomi/repo1"
+
+Summary: interrupted, 0 repositories succeeded, 0 failed, 1 stopped partway, 1 not started
+  tnagatomi/repo1: 1 created, 0 updated, 0 deleted, 0 unchanged (interrupted)
+Not started:
+  tnagatomi/repo2
+`
+	if got := stripProgress(out.String()); got != want {
+		t.Errorf("Apply() output = %q, want %q", got, want)
+	}
+}
+
+func TestApply_InterruptedRelabel(t *testing.T) {
+	repo := option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Ctrl-C cancels the removal of the source label of the first item
+	m := &mock.MockAPI{
+		RemoveLabelsFromLabelableFunc: func(labelableID option.GraphQLID, labelIDs []option.GraphQLID) error {
+			cancel()
+			return fmt.Errorf("request failed: %w", context.Canceled)
+		},
+	}
+	e := &Executor{api: m}
+
+	plan := &Plan{
+		Command: CommandMerge,
+		Repos: []*RepoPlan{{
+			Repo: repo,
+			Operations: []Operation{
+				{Type: OperationRelabel, Before: &option.Label{Name: "old"}, After: &option.Label{Name: "new"}, Item: &option.Labelable{ID: "I_1", Number: 1, Type: option.LabelableTypeIssue}},
+				{Type: OperationRelabel, Before: &option.Label{Name: "old"}, After: &option.Label{Name: "new"}, Item: &option.Labelable{ID: "I_2", Number: 2, Type: option.LabelableTypeIssue}},
+				{Type: OperationDelete, Before: &option.Label{Name: "old"}},
+			},
+			LabelIDs: map[string]option.GraphQLID{"old": "LA_old", "new": "LA_new"},
+		}},
+	}
+
+	out := &bytes.Buffer{}
+	err := e.Apply(ctx, out, plan)
+	if !errors.Is(err, ErrInterrupted) {
+		t.Errorf("Apply() error = %v, want %v", err, ErrInterrupted)
+	}
+	if len(m.AddLabelsToLabelableCalls) != 1 || len(m.RemoveLabelsFromLabelableCalls) != 1 {
+		t.Errorf("AddLabelsToLabelable() calls = %d, RemoveLabelsFromLabelable() calls = %d, want 1 each", len(m.AddLabelsToLabelableCalls), len(m.RemoveLabelsFromLabelableCalls))
+	}
+	if len(m.DeleteLabelCalls) > 0 {
+		t.Errorf("Apply() deleted the source label after being interrupted")
+	}
+	if got := out.String(); strings.Contains(got, "Failed") || !strings.Contains(got, `Interrupted before finishing the operations for repository "tnagatomi/mock-repo"`) {
+		t.Errorf("Apply() output = %q, want the repository reported as interrupted, not failed", got)
+	}
+}
+
+func TestList_Excluded(t *testing.T) {
+	m := &mock.MockAPI{
+		ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+			return []option.Label{{Name: "bug", Color: "d73a4a"}}, nil
+		},
+	}
+	e := &Executor{api: m}
+	e.SetRepoExcludes(option.RepoPatterns{"tnagatomi/legacy-*"})
+
+	out := &bytes.Buffer{}
+	repos := []option.Repo{{Owner: "tnagatomi", Repo: "repo"}, {Owner: "tnagatomi", Repo: "legacy-app"}}
+	if err := e.List(context.Background(), out, repos); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	want := `Skipped repository "tnagatomi/legacy-app": excluded
+Labels for repository "tnagatomi/repo":
+  bug (#d73a4a)
+
+Summary: all operations completed successfully
+`
+	if diff := cmp.Diff(want, stripProgress(out.String())); diff != "" {
+		t.Errorf("List() output mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tnagatomi/gh-fuda/option"
+	"github.com/tnagatomi/gh-fuda/parser"
+)
+
+// ExportFormat is the file format labels are exported in
+type ExportFormat string
+
+const (
+	ExportFormatYAML ExportFormat = "yaml"
+	ExportFormatJSON ExportFormat = "json"
+)
+
+// ParseExportFormat returns the export format named s
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch ExportFormat(strings.ToLower(s)) {
+	case ExportFormatYAML, "yml":
+		return ExportFormatYAML, nil
+	case ExportFormatJSON:
+		return ExportFormatJSON, nil
+	}
+	return "", fmt.Errorf("invalid export format %q: must be yaml or json", s)
+}
+
+// Export writes the labels of the repositories in a format create and sync
+// can read back.
+//
+// With split, each repository is written to <path>/<owner>/<repo>.<format>,
+// or <path>/<host>/<owner>/<repo>.<format> for repositories of other hosts than
+// the default one, path defaulting to the current directory. Otherwise the labels of all
+// repositories are merged into a single file at path, or written to out if
+// path is empty. When repositories disagree on a label, the first one wins;
+// the others are reported to errOut, so that they never mix with labels
+// written to out, and annotated as comments in YAML. Excluded repositories and
+// failures are reported to errOut too.
+func (e *Executor) Export(ctx context.Context, out, errOut io.Writer, repos []option.Repo, format ExportFormat, path string, split bool) error {
+	repos, excluded, err := e.resolveRepos(ctx, repos)
+	if err != nil {
+		return err
+	}
+	printExcluded(errOut, excluded)
+
+	e.prefetchLabels(ctx, repos)
+	defer e.clearPrefetched()
+
+	wp := e.newWorkerPool(out)
+	jobs := make([]Job, len(repos))
+	repoLabels := make([][]option.Label, len(repos))
+	listed := make([]bool, len(repos))
+
+	for i, repo := range repos {
+		jobs[i] = Job{
+			ID:  i,
+			Key: e.ownerKey(repo),
+			Func: func(ctx context.Context) *JobResult {
+				labels, err := e.listLabels(ctx, repo)
+				if err != nil {
+					return &JobResult{
+						Output:  fmt.Sprintf("Failed to list labels for repository %q: %v\n", repo, err),
+						Success: false,
+						Errors:  []error{err},
+					}
+				}
+				repoLabels[i] = labels
+				listed[i] = true
+				return &JobResult{Success: true}
+			},
+		}
+	}
+
+	results := wp.Run(ctx, jobs)
+	wp.ClearProgress()
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w before every repository was listed, nothing was exported", ErrInterrupted)
+	}
+
+	failed := false
+	for _, result := range results {
+		if !result.Success {
+			_, _ = fmt.Fprint(errOut, result.Output)
+			failed = true
+		}
+	}
+
+	if split {
+		return e.exportSplit(out, errOut, repos, repoLabels, listed, format, path)
+	}
+	if failed {
+		return fmt.Errorf("some operations failed")
+	}
+
+	labels, conflicts := mergeLabels(repos, repoLabels)
+	for i, label := range labels {
+		for _, conflict := range conflicts[i] {
+			_, _ = fmt.Fprintf(errOut, "Label %q conflicts: %s\n", label.Name, conflict)
+		}
+	}
+
+	var data []byte
+	switch format {
+	case ExportFormatJSON:
+		data, err = parser.LabelToJSON(labels)
+	default:
+		data, err = parser.LabelToYAML(labels, conflictComments(conflicts))
+	}
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		_, err = out.Write(data)
+		return err
+	}
+
+	if err := writeExport(path, data); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Exported %d labels to %s\n", len(labels), path)
+	return nil
+}
+
+// exportSplit writes the labels of each repository that could be listed to
+// its own file
+func (e *Executor) exportSplit(out, errOut io.Writer, repos []option.Repo, repoLabels [][]option.Label, listed []bool, format ExportFormat, dir string) error {
+	if dir == "" {
+		dir = "."
+	}
+
+	failed := false
+	for i, repo := range repos {
+		if !listed[i] {
+			failed = true
+			continue
+		}
+
+		var data []byte
+		var err error
+		switch format {
+		case ExportFormatJSON:
+			data, err = parser.LabelToJSON(repoLabels[i])
+		default:
+			data, err = parser.LabelToYAML(repoLabels[i], nil)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Host is empty for the default host, which filepath.Join leaves out
+		path := filepath.Join(dir, repo.Host, repo.Owner, repo.Repo+"."+string(format))
+		if err := writeExport(path, data); err != nil {
+			_, _ = fmt.Fprintf(errOut, "Failed to export labels for repository %q: %v\n", repo, err)
+			failed = true
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "Exported %d labels for repository %q to %s\n", len(repoLabels[i]), repo, path)
+	}
+
+	if failed {
+		return fmt.Errorf("some operations failed")
+	}
+	return nil
+}
+
+// writeExport writes data to path, creating its directory if needed
+func writeExport(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// mergeLabels returns the union of the labels of all repositories, matched
+// case-insensitively by name, in the order they are first seen. For each
+// merged label, conflicts describes the repositories whose label differs from
+// the one kept.
+func mergeLabels(repos []option.Repo, repoLabels [][]option.Label) ([]option.Label, [][]string) {
+	var labels []option.Label
+	var conflicts [][]string
+
+	for i, repo := range repos {
+		for _, label := range repoLabels[i] {
+			j := indexOfLabel(label.Name, labels)
+			if j < 0 {
+				labels = append(labels, label)
+				conflicts = append(conflicts, nil)
+				continue
+			}
+			// An empty description is a difference here, unlike when
+			// comparing with the desired labels, as both sides are existing
+			if labelChanges(labels[j], label) != "" || labels[j].Description != label.Description {
+				conflicts[j] = append(conflicts[j], fmt.Sprintf("repository %q has %s", repo, describeLabel(label)))
+			}
+		}
+	}
+
+	return labels, conflicts
+}
+
+// conflictComments turns the conflicts of each label into a comment
+func conflictComments(conflicts [][]string) []string {
+	comments := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		if len(c) > 0 {
+			comments[i] = "conflict: " + strings.Join(c, "\nconflict: ")
+		}
+	}
+	return comments
+}
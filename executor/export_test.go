@@ -0,0 +1,262 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tnagatomi/gh-fuda/api"
+	"github.com/tnagatomi/gh-fuda/internal/mock"
+	"github.com/tnagatomi/gh-fuda/option"
+	"github.com/tnagatomi/gh-fuda/parser"
+)
+
+func exportMock() *mock.MockAPI {
+	return &mock.MockAPI{
+		ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+			switch repo.Repo {
+			case "repo-1":
+				return []option.Label{
+					{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+					{Name: "question", Color: "d876e3"},
+				}, nil
+			case "repo-2":
+				return []option.Label{
+					{Name: "Bug", Color: "ff0000", Description: "Something isn't working"},
+					{Name: "enhancement", Color: "a2eeef"},
+				}, nil
+			}
+			return nil, errors.New("not found")
+		},
+	}
+}
+
+func TestExport_Stdout(t *testing.T) {
+	e := &Executor{api: exportMock()}
+	out := &bytes.Buffer{}
+
+	err := e.Export(context.Background(), out, &bytes.Buffer{}, []option.Repo{{Owner: "owner", Repo: "repo-1"}}, ExportFormatJSON, "", false)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	want := `[
+  {
+    "name": "bug",
+    "color": "d73a4a",
+    "description": "Something isn't working"
+  },
+  {
+    "name": "question",
+    "color": "d876e3",
+    "description": ""
+  }
+]
+`
+	if diff := cmp.Diff(want, stripProgress(out.String())); diff != "" {
+		t.Errorf("Export() output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExport_StdoutConflicts(t *testing.T) {
+	e := &Executor{api: exportMock()}
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+
+	repos := []option.Repo{{Owner: "owner", Repo: "repo-1"}, {Owner: "owner", Repo: "repo-2"}}
+	if err := e.Export(context.Background(), out, errOut, repos, ExportFormatJSON, "", false); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	var labels []parser.JSONLabel
+	if err := json.Unmarshal(out.Bytes(), &labels); err != nil {
+		t.Fatalf("Export() output is not valid JSON: %v", err)
+	}
+	if len(labels) != 3 {
+		t.Errorf("Export() exported %d labels, want 3", len(labels))
+	}
+	wantErrOut := `Label "bug" conflicts: repository "owner/repo-2" has "Bug" (#ff0000) - Something isn't working
+`
+	if diff := cmp.Diff(wantErrOut, errOut.String()); diff != "" {
+		t.Errorf("Export() error output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExport_Excluded(t *testing.T) {
+	e := &Executor{api: exportMock()}
+	e.SetRepoExcludes(option.RepoPatterns{"owner/repo-2"})
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+
+	repos := []option.Repo{{Owner: "owner", Repo: "repo-1"}, {Owner: "owner", Repo: "repo-2"}}
+	if err := e.Export(context.Background(), out, errOut, repos, ExportFormatJSON, "", false); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	var labels []parser.JSONLabel
+	if err := json.Unmarshal(out.Bytes(), &labels); err != nil {
+		t.Fatalf("Export() output is not valid JSON: %v", err)
+	}
+	wantErrOut := `Skipped repository "owner/repo-2": excluded
+`
+	if diff := cmp.Diff(wantErrOut, errOut.String()); diff != "" {
+		t.Errorf("Export() error output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExport_Merged(t *testing.T) {
+	e := &Executor{api: exportMock()}
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	path := filepath.Join(t.TempDir(), "labels.yaml")
+
+	repos := []option.Repo{{Owner: "owner", Repo: "repo-1"}, {Owner: "owner", Repo: "repo-2"}}
+	if err := e.Export(context.Background(), out, errOut, repos, ExportFormatYAML, path, false); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	wantOut := "Exported 3 labels to " + path + "\n"
+	if diff := cmp.Diff(wantOut, stripProgress(out.String())); diff != "" {
+		t.Errorf("Export() output mismatch (-want +got):\n%s", diff)
+	}
+	wantErrOut := `Label "bug" conflicts: repository "owner/repo-2" has "Bug" (#ff0000) - Something isn't working
+`
+	if diff := cmp.Diff(wantErrOut, errOut.String()); diff != "" {
+		t.Errorf("Export() error output mismatch (-want +got):\n%s", diff)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read exported file: %v", err)
+	}
+	wantFile := `# conflict: repository "owner/repo-2" has "Bug" (#ff0000) - Something isn't working
+- name: bug
+  color: d73a4a
+  description: Something isn't working
+- name: question
+  color: d876e3
+  description: ""
+- name: enhancement
+  color: a2eeef
+  description: ""
+`
+	if diff := cmp.Diff(wantFile, string(data)); diff != "" {
+		t.Errorf("Export() file mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExport_Split(t *testing.T) {
+	e := &Executor{
+		api:      exportMock(),
+		host:     "github.com",
+		hostAPIs: map[string]api.APIClient{"ghe.example.com": exportMock()},
+	}
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	dir := t.TempDir()
+
+	repos := []option.Repo{
+		{Owner: "owner", Repo: "repo-1"},
+		{Owner: "owner", Repo: "missing"},
+		{Owner: "owner", Repo: "repo-2"},
+		{Host: "ghe.example.com", Owner: "owner", Repo: "repo-1"},
+	}
+	err := e.Export(context.Background(), out, errOut, repos, ExportFormatJSON, dir, true)
+	if err == nil {
+		t.Errorf("Export() should fail when a repository cannot be listed")
+	}
+
+	// The same repository name on another host gets its own file
+	files := []string{
+		filepath.Join(dir, "owner", "repo-1.json"),
+		filepath.Join(dir, "owner", "repo-2.json"),
+		filepath.Join(dir, "ghe.example.com", "owner", "repo-1.json"),
+	}
+	for _, name := range files {
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("Export() did not write %s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "owner", "missing.json")); err == nil {
+		t.Errorf("Export() wrote a file for a repository that could not be listed")
+	}
+
+	wantOut := `Exported 2 labels for repository "owner/repo-1" to ` + files[0] + `
+Exported 2 labels for repository "owner/repo-2" to ` + files[1] + `
+Exported 2 labels for repository "ghe.example.com/owner/repo-1" to ` + files[2] + `
+`
+	if diff := cmp.Diff(wantOut, stripProgress(out.String())); diff != "" {
+		t.Errorf("Export() output mismatch (-want +got):\n%s", diff)
+	}
+	wantErrOut := `Failed to list labels for repository "owner/missing": not found
+`
+	if diff := cmp.Diff(wantErrOut, errOut.String()); diff != "" {
+		t.Errorf("Export() error output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExport_StdoutFailure(t *testing.T) {
+	e := &Executor{api: exportMock()}
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+
+	repos := []option.Repo{{Owner: "owner", Repo: "repo-1"}, {Owner: "owner", Repo: "missing"}}
+	if err := e.Export(context.Background(), out, errOut, repos, ExportFormatJSON, "", false); err == nil {
+		t.Errorf("Export() should fail when a repository cannot be listed")
+	}
+
+	// Nothing but exported labels is ever written to out
+	if got := stripProgress(out.String()); got != "" {
+		t.Errorf("Export() output = %q, want none", got)
+	}
+	if !strings.Contains(errOut.String(), `Failed to list labels for repository "owner/missing"`) {
+		t.Errorf("Export() error output = %q, want the failure", errOut.String())
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ExportFormat
+		wantErr bool
+	}{
+		{input: "yaml", want: ExportFormatYAML},
+		{input: "yml", want: ExportFormatYAML},
+		{input: "JSON", want: ExportFormatJSON},
+		{input: "toml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseExportFormat(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseExportFormat(%q) = %q, %v", tt.input, got, err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tnagatomi/gh-fuda/api"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+// connect creates the API client of the repository's host, if not done yet.
+// Repositories of the default host use the executor's own client. Clients are
+// only created before jobs run, so apiFor can be called from any job.
+func (e *Executor) connect(repo option.Repo) error {
+	if repo.Host == "" {
+		return nil
+	}
+	if _, ok := e.hostAPIs[repo.Host]; ok {
+		return nil
+	}
+
+	client, err := e.newAPI(repo.Host)
+	if err != nil {
+		return fmt.Errorf("failed to initialize API client for host %q: %v", repo.Host, err)
+	}
+	if e.hostAPIs == nil {
+		e.hostAPIs = make(map[string]api.APIClient)
+	}
+	e.hostAPIs[repo.Host] = client
+	return nil
+}
+
+// apiFor returns the API client of the repository's host
+func (e *Executor) apiFor(repo option.Repo) api.APIClient {
+	if client, ok := e.hostAPIs[repo.Host]; ok {
+		return client
+	}
+	return e.api
+}
+
+// localRepo strips the host of a repository of the default host, so it is
+// the same as the repository given without a host
+func (e *Executor) localRepo(repo option.Repo) option.Repo {
+	if strings.EqualFold(repo.Host, e.host) {
+		repo.Host = ""
+	}
+	return repo
+}
+
+// localPatterns strips the host of HOST/OWNER/REPO patterns of the default
+// host, so they match the repositories of the default host
+func (e *Executor) localPatterns(patterns option.RepoPatterns) option.RepoPatterns {
+	local := make(option.RepoPatterns, len(patterns))
+	for i, pattern := range patterns {
+		if host, rest, ok := strings.Cut(pattern, "/"); ok && strings.Count(rest, "/") == 1 && strings.EqualFold(host, e.host) {
+			pattern = rest
+		}
+		local[i] = pattern
+	}
+	return local
+}
+
+// rateLimitDelay returns how long to wait before starting a job with the
+// given ownerKey, to stay within the rate limit of the host of the owner. The
+// jobs of other hosts are not held back.
+func (e *Executor) rateLimitDelay(key string) time.Duration {
+	host, _, _ := strings.Cut(key, "/")
+	return e.apiFor(e.localRepo(option.Repo{Host: host})).RateLimitDelay()
+}
+
+// newWorkerPool returns a worker pool running as many jobs at once as the
+// concurrency settings allow, whose jobs wait for the rate limits
+func (e *Executor) newWorkerPool(out io.Writer) *WorkerPool {
+	return NewWorkerPool(out,
+		WithWorkers(e.concurrency),
+		WithKeyLimit(e.ownerConcurrency),
+		WithThrottle(e.rateLimitDelay),
+	)
+}
+
+// ownerKey returns the job key of the repositories of the owner of repo, for
+// SetOwnerConcurrency to limit together
+func (e *Executor) ownerKey(repo option.Repo) string {
+	host := repo.Host
+	if host == "" {
+		host = e.host
+	}
+	return strings.ToLower(host + "/" + repo.Owner)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"context"
+	"slices"
+
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+// prefetchedLabels are the labels of a repository, or the error listing them
+type prefetchedLabels struct {
+	labels []option.Label
+	err    error
+}
+
+// prefetchLabels lists the labels of the repositories for listLabels to
+// return, with one request per batchSize repositories of a host instead of
+// one request per repository. It must be called before jobs run, as they read
+// the result concurrently, and undone with clearPrefetched once they are done.
+func (e *Executor) prefetchLabels(ctx context.Context, repos []option.Repo) {
+	var hosts []string
+	byHost := make(map[string][]option.Repo)
+	for _, repo := range repos {
+		if _, ok := byHost[repo.Host]; !ok {
+			hosts = append(hosts, repo.Host)
+		}
+		byHost[repo.Host] = append(byHost[repo.Host], repo)
+	}
+
+	e.prefetched = make(map[option.Repo]prefetchedLabels, len(repos))
+	for _, host := range hosts {
+		client := e.apiFor(byHost[host][0])
+		for batch := range slices.Chunk(byHost[host], e.batchLimit()) {
+			if ctx.Err() != nil {
+				// The jobs left do not start either
+				return
+			}
+			labels, errs := client.ListLabelsOfRepos(ctx, batch)
+			for i, repo := range batch {
+				e.prefetched[repo] = prefetchedLabels{labels: labels[i], err: errs[i]}
+			}
+		}
+	}
+}
+
+// clearPrefetched forgets the prefetched labels, which get stale as soon as
+// labels are changed
+func (e *Executor) clearPrefetched() {
+	e.prefetched = nil
+}
+
+// listLabels returns the labels of the repository, as prefetched if they were
+func (e *Executor) listLabels(ctx context.Context, repo option.Repo) ([]option.Label, error) {
+	if p, ok := e.prefetched[repo]; ok {
+		return p.labels, p.err
+	}
+	return e.apiFor(repo).ListLabels(ctx, repo)
+}
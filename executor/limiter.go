@@ -0,0 +1,74 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// mutationLimiter spaces out mutations sent by every worker to an average of
+// perMinute a minute, keeping clear of the secondary rate limits of GitHub on
+// content-creating requests
+type mutationLimiter struct {
+	mu sync.Mutex
+	// interval is the time reserved for each mutation
+	interval time.Duration
+	// next is when the next mutation may be sent
+	next  time.Time
+	now   func() time.Time
+	sleep func(context.Context, time.Duration)
+}
+
+// newMutationLimiter returns a limiter of perMinute mutations a minute, or nil
+// for no limit if perMinute is not positive
+func newMutationLimiter(perMinute int) *mutationLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &mutationLimiter{
+		interval: time.Minute / time.Duration(perMinute),
+		now:      time.Now,
+		sleep:    sleep,
+	}
+}
+
+// wait reserves the time of n mutations and waits until they may be sent, or
+// until ctx is canceled. A nil limiter never waits.
+func (l *mutationLimiter) wait(ctx context.Context, n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+	l.mu.Lock()
+	now := l.now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(time.Duration(n) * l.interval)
+	l.mu.Unlock()
+
+	if d := start.Sub(now); d > 0 {
+		l.sleep(ctx, d)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMutationLimiter_Wait(t *testing.T) {
+	l := newMutationLimiter(60)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	var slept []time.Duration
+	l.sleep = func(_ context.Context, d time.Duration) { slept = append(slept, d) }
+
+	// The first batch is sent at once, the next ones after the time reserved
+	// for the mutations before them
+	ctx := context.Background()
+	l.wait(ctx, 3)
+	l.wait(ctx, 1)
+	l.wait(ctx, 1)
+	now = now.Add(10 * time.Second)
+	l.wait(ctx, 1)
+
+	want := []time.Duration{3 * time.Second, 4 * time.Second}
+	if len(slept) != len(want) || slept[0] != want[0] || slept[1] != want[1] {
+		t.Errorf("slept = %v, want %v", slept, want)
+	}
+}
+
+func TestMutationLimiter_Unlimited(t *testing.T) {
+	if l := newMutationLimiter(0); l != nil {
+		t.Fatalf("newMutationLimiter(0) = %v, want nil", l)
+	}
+	// A nil limiter never waits
+	var l *mutationLimiter
+	l.wait(context.Background(), 100)
+}
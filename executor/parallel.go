@@ -22,23 +22,34 @@ THE SOFTWARE.
 package executor
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"sync"
+	"time"
 
 	"golang.org/x/term"
 )
 
 const (
-	// WorkerPoolSize is the number of concurrent workers for parallel operations
+	// WorkerPoolSize is the number of concurrent workers for parallel
+	// operations, unless set otherwise with WithWorkers
 	WorkerPoolSize = 5
 )
 
+// minReportedWait is the shortest rate limit wait reported when the output is
+// not a terminal, where the report cannot be overwritten by the progress
+const minReportedWait = 10 * time.Second
+
 // Job represents a unit of work to be executed by a worker
 type Job struct {
-	ID   int
-	Func func() *JobResult
+	ID int
+	// Key groups the jobs limited together by WithKeyLimit, such as those of
+	// the repositories of one owner. Empty is never limited.
+	Key  string
+	Func func(ctx context.Context) *JobResult
 }
 
 // JobResult represents the result of a job execution
@@ -47,12 +58,18 @@ type JobResult struct {
 	Output  string
 	Success bool
 	Errors  []error
+	Counts  OperationCounts
+	// Interrupted is true if the job stopped before finishing its work
+	// because the context was canceled
+	Interrupted bool
+	// NotStarted is true if the job never ran because the context was
+	// canceled first
+	NotStarted bool
 }
 
 // WorkerPool manages parallel job execution with a fixed number of workers
 type WorkerPool struct {
 	workers    int
-	jobs       chan Job
 	results    chan *JobResult
 	wg         sync.WaitGroup
 	out        io.Writer
@@ -60,39 +77,97 @@ type WorkerPool struct {
 	totalJobs  int
 	completed  int
 	mu         sync.Mutex
+	// throttle returns how long to wait before starting a job with the given
+	// key, to stay within API rate limits. Nil never waits.
+	throttle func(key string) time.Duration
+	sleep    func(context.Context, time.Duration)
+	// waitingUntil is when the wait reported last ends
+	waitingUntil time.Time
+	// keyLimit is the maximum number of jobs with the same key run at once,
+	// 0 for no limit
+	keyLimit int
+	// pending holds the jobs not started yet
+	pending []Job
+	// running counts the running jobs by key
+	running map[string]int
+	// ready is signaled when a job finishes, so that a job held back by
+	// keyLimit may start
+	ready *sync.Cond
+}
+
+// WorkerPoolOption configures a WorkerPool created by NewWorkerPool
+type WorkerPoolOption func(*WorkerPool)
+
+// WithWorkers sets the number of jobs run at once. Values below 1 keep the
+// default of WorkerPoolSize.
+func WithWorkers(n int) WorkerPoolOption {
+	return func(wp *WorkerPool) {
+		if n >= 1 {
+			wp.workers = n
+		}
+	}
 }
 
-// NewWorkerPool creates a new worker pool with the specified number of workers
-func NewWorkerPool(out io.Writer) *WorkerPool {
+// WithKeyLimit sets the maximum number of jobs with the same key run at once,
+// such as to keep the repositories of one owner from being operated on by
+// every worker. 0 sets no limit.
+func WithKeyLimit(n int) WorkerPoolOption {
+	return func(wp *WorkerPool) {
+		wp.keyLimit = max(n, 0)
+	}
+}
+
+// WithThrottle makes workers wait as long as throttle returns for the key of
+// each job before starting it
+func WithThrottle(throttle func(key string) time.Duration) WorkerPoolOption {
+	return func(wp *WorkerPool) {
+		wp.throttle = throttle
+	}
+}
+
+// NewWorkerPool creates a new worker pool configured by opts, with
+// WorkerPoolSize workers by default
+func NewWorkerPool(out io.Writer, opts ...WorkerPoolOption) *WorkerPool {
 	isTTY := false
 	if f, ok := out.(*os.File); ok {
 		isTTY = term.IsTerminal(int(f.Fd()))
 	}
-	return &WorkerPool{
+	wp := &WorkerPool{
 		workers: WorkerPoolSize,
 		out:     out,
 		isTTY:   isTTY,
+		sleep:   sleep,
+	}
+	for _, opt := range opts {
+		opt(wp)
 	}
+	return wp
 }
 
-// Run executes all jobs in parallel and returns results in order
-func (wp *WorkerPool) Run(jobs []Job) []*JobResult {
+// Run executes all jobs in parallel and returns results in order. Once ctx is
+// canceled no job is started, and the results of the jobs left are marked
+// NotStarted. Jobs already running are passed ctx to stop early.
+func (wp *WorkerPool) Run(ctx context.Context, jobs []Job) []*JobResult {
 	wp.totalJobs = len(jobs)
 	wp.completed = 0
-	wp.jobs = make(chan Job, len(jobs))
+	wp.pending = slices.Clone(jobs)
+	wp.running = make(map[string]int)
+	wp.ready = sync.NewCond(&wp.mu)
 	wp.results = make(chan *JobResult, len(jobs))
 
+	// Wake up the workers waiting for a job to start, so that they stop
+	stop := context.AfterFunc(ctx, func() {
+		wp.mu.Lock()
+		wp.ready.Broadcast()
+		wp.mu.Unlock()
+	})
+	defer stop()
+
 	// Start workers
 	for i := 0; i < wp.workers; i++ {
 		wp.wg.Add(1)
-		go wp.worker()
-	}
-
-	// Send jobs to workers
-	for _, job := range jobs {
-		wp.jobs <- job
+		go wp.worker(ctx)
 	}
-	close(wp.jobs)
 
 	// Wait for all workers to complete
 	wp.wg.Wait()
@@ -107,20 +182,36 @@ func (wp *WorkerPool) Run(jobs []Job) []*JobResult {
 	// Return results in order
 	orderedResults := make([]*JobResult, len(jobs))
 	for i := range jobs {
-		orderedResults[i] = resultsMap[i]
+		result, ok := resultsMap[i]
+		if !ok {
+			result = &JobResult{ID: i, NotStarted: true}
+		}
+		orderedResults[i] = result
 	}
 
 	return orderedResults
 }
 
-// worker processes jobs from the jobs channel
-func (wp *WorkerPool) worker() {
+// worker processes pending jobs until none is left
+func (wp *WorkerPool) worker(ctx context.Context) {
 	defer wp.wg.Done()
-	for job := range wp.jobs {
-		result := job.Func()
+	for {
+		job, ok := wp.next(ctx)
+		if !ok {
+			return
+		}
+		wp.wait(ctx, job.Key)
+		if ctx.Err() != nil {
+			// Canceled while waiting for the rate limit, so the job is left
+			// not started like those still pending
+			return
+		}
+		result := job.Func(ctx)
 		result.ID = job.ID
 
 		wp.mu.Lock()
+		wp.running[job.Key]--
+		wp.ready.Broadcast()
 		wp.completed++
 		if wp.isTTY {
 			_, _ = fmt.Fprintf(wp.out, "\rProcessing: %d/%d repositories completed", wp.completed, wp.totalJobs)
@@ -131,6 +222,62 @@ func (wp *WorkerPool) worker() {
 	}
 }
 
+// next removes the first pending job that keyLimit lets run from the queue
+// and returns it, waiting for running jobs to finish if none can. It returns
+// false when no job is left or ctx is canceled.
+func (wp *WorkerPool) next(ctx context.Context) (Job, bool) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	for len(wp.pending) > 0 && ctx.Err() == nil {
+		for i, job := range wp.pending {
+			if wp.keyLimit == 0 || job.Key == "" || wp.running[job.Key] < wp.keyLimit {
+				wp.pending = slices.Delete(wp.pending, i, i+1)
+				wp.running[job.Key]++
+				return job, true
+			}
+		}
+		wp.ready.Wait()
+	}
+	return Job{}, false
+}
+
+// wait waits as long as the throttle says for a job with the key, or until ctx
+// is canceled, reporting the wait unless another worker already reported it
+func (wp *WorkerPool) wait(ctx context.Context, key string) {
+	if wp.throttle == nil {
+		return
+	}
+	d := wp.throttle(key)
+	if d <= 0 {
+		return
+	}
+
+	until := time.Now().Add(d)
+	wp.mu.Lock()
+	if until.Sub(wp.waitingUntil) >= time.Second && (wp.isTTY || d >= minReportedWait) {
+		wp.waitingUntil = until
+		wait := d.Round(time.Second)
+		if wp.isTTY {
+			_, _ = fmt.Fprintf(wp.out, "\r\033[KWaiting %s for the API rate limit (until %s)", wait, until.Format(time.TimeOnly))
+		} else {
+			_, _ = fmt.Fprintf(wp.out, "Waiting %s for the API rate limit (until %s)\n", wait, until.Format(time.TimeOnly))
+		}
+	}
+	wp.mu.Unlock()
+
+	wp.sleep(ctx, d)
+}
+
+// sleep waits for d, or until ctx is canceled
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
 // ClearProgress clears the progress line and moves to a new line
 func (wp *WorkerPool) ClearProgress() {
 	if wp.isTTY {
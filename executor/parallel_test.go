@@ -23,7 +23,10 @@ package executor
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -38,7 +41,7 @@ func TestWorkerPool_Run_OrderPreserved(t *testing.T) {
 		id := i
 		jobs[i] = Job{
 			ID: id,
-			Func: func() *JobResult {
+			Func: func(context.Context) *JobResult {
 				return &JobResult{
 					ID:      id,
 					Output:  "result",
@@ -48,7 +51,7 @@ func TestWorkerPool_Run_OrderPreserved(t *testing.T) {
 		}
 	}
 
-	results := wp.Run(jobs)
+	results := wp.Run(context.Background(), jobs)
 
 	if len(results) != 10 {
 		t.Fatalf("expected 10 results, got %d", len(results))
@@ -73,7 +76,7 @@ func TestWorkerPool_Run_ConcurrencyLimit(t *testing.T) {
 	for i := 0; i < 20; i++ {
 		jobs[i] = Job{
 			ID: i,
-			Func: func() *JobResult {
+			Func: func(context.Context) *JobResult {
 				// Track concurrent executions
 				current := atomic.AddInt32(&concurrent, 1)
 
@@ -97,7 +100,7 @@ func TestWorkerPool_Run_ConcurrencyLimit(t *testing.T) {
 		}
 	}
 
-	wp.Run(jobs)
+	wp.Run(context.Background(), jobs)
 
 	max := atomic.LoadInt32(&maxConcurrent)
 	if max > int32(WorkerPoolSize) {
@@ -114,25 +117,25 @@ func TestWorkerPool_Run_ErrorHandling(t *testing.T) {
 	jobs := []Job{
 		{
 			ID: 0,
-			Func: func() *JobResult {
+			Func: func(context.Context) *JobResult {
 				return &JobResult{Success: true}
 			},
 		},
 		{
 			ID: 1,
-			Func: func() *JobResult {
+			Func: func(context.Context) *JobResult {
 				return &JobResult{Success: false, Errors: []error{expectedErr}}
 			},
 		},
 		{
 			ID: 2,
-			Func: func() *JobResult {
+			Func: func(context.Context) *JobResult {
 				return &JobResult{Success: true}
 			},
 		},
 	}
 
-	results := wp.Run(jobs)
+	results := wp.Run(context.Background(), jobs)
 
 	if len(results) != 3 {
 		t.Fatalf("expected 3 results, got %d", len(results))
@@ -157,12 +160,12 @@ func TestWorkerPool_Run_NoProgressForNonTTY(t *testing.T) {
 	wp := NewWorkerPool(&buf)
 
 	jobs := []Job{
-		{ID: 0, Func: func() *JobResult { return &JobResult{Success: true} }},
-		{ID: 1, Func: func() *JobResult { return &JobResult{Success: true} }},
-		{ID: 2, Func: func() *JobResult { return &JobResult{Success: true} }},
+		{ID: 0, Func: func(context.Context) *JobResult { return &JobResult{Success: true} }},
+		{ID: 1, Func: func(context.Context) *JobResult { return &JobResult{Success: true} }},
+		{ID: 2, Func: func(context.Context) *JobResult { return &JobResult{Success: true} }},
 	}
 
-	wp.Run(jobs)
+	wp.Run(context.Background(), jobs)
 
 	output := buf.String()
 	// Progress output should NOT be shown for non-TTY output
@@ -175,9 +178,150 @@ func TestWorkerPool_Run_EmptyJobs(t *testing.T) {
 	var buf bytes.Buffer
 	wp := NewWorkerPool(&buf)
 
-	results := wp.Run([]Job{})
+	results := wp.Run(context.Background(), []Job{})
 
 	if len(results) != 0 {
 		t.Errorf("expected 0 results, got %d", len(results))
 	}
-}
\ No newline at end of file
+}
+func TestWorkerPool_Run_Throttle(t *testing.T) {
+	var buf bytes.Buffer
+
+	// The rate limit is exhausted until the first wait is over
+	var waits int32
+	wp := NewWorkerPool(&buf, WithWorkers(1), WithThrottle(func(string) time.Duration {
+		if atomic.LoadInt32(&waits) == 0 {
+			return time.Minute
+		}
+		return 0
+	}))
+	var slept []time.Duration
+	wp.sleep = func(_ context.Context, d time.Duration) {
+		slept = append(slept, d)
+		atomic.AddInt32(&waits, 1)
+	}
+
+	jobs := []Job{
+		{ID: 0, Func: func(context.Context) *JobResult { return &JobResult{Success: true} }},
+		{ID: 1, Func: func(context.Context) *JobResult { return &JobResult{Success: true} }},
+	}
+	results := wp.Run(context.Background(), jobs)
+
+	if len(results) != 2 || !results[0].Success || !results[1].Success {
+		t.Errorf("results = %v, want every job to succeed", results)
+	}
+	if len(slept) != 1 || slept[0] != time.Minute {
+		t.Errorf("slept = %v, want one wait of 1m", slept)
+	}
+	if !strings.HasPrefix(buf.String(), "Waiting 1m0s for the API rate limit (until ") {
+		t.Errorf("output = %q, want the wait to be reported", buf.String())
+	}
+}
+
+// trackMax returns a job function recording the highest value of *running
+// while jobs run
+func trackMax(running, highest *int32) func(context.Context) *JobResult {
+	return func(context.Context) *JobResult {
+		current := atomic.AddInt32(running, 1)
+		for {
+			seen := atomic.LoadInt32(highest)
+			if current <= seen || atomic.CompareAndSwapInt32(highest, seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(running, -1)
+		return &JobResult{Success: true}
+	}
+}
+
+func TestWorkerPool_Run_WithWorkers(t *testing.T) {
+	var buf bytes.Buffer
+	wp := NewWorkerPool(&buf, WithWorkers(2))
+
+	var running, highest int32
+	jobs := make([]Job, 10)
+	for i := range jobs {
+		jobs[i] = Job{ID: i, Func: trackMax(&running, &highest)}
+	}
+	wp.Run(context.Background(), jobs)
+
+	if highest != 2 {
+		t.Errorf("max concurrent = %d, want 2", highest)
+	}
+}
+
+func TestWorkerPool_Run_KeyLimit(t *testing.T) {
+	var buf bytes.Buffer
+	wp := NewWorkerPool(&buf, WithWorkers(4), WithKeyLimit(1))
+
+	// Jobs of the same key run one at a time, while jobs of other keys still
+	// run alongside them
+	var mu sync.Mutex
+	running := make(map[string]int)
+	highest := make(map[string]int)
+	jobs := make([]Job, 8)
+	for i := range jobs {
+		key := []string{"a", "b"}[i%2]
+		jobs[i] = Job{ID: i, Key: key, Func: func(context.Context) *JobResult {
+			mu.Lock()
+			running[key]++
+			running[""]++
+			highest[key] = max(highest[key], running[key])
+			highest[""] = max(highest[""], running[""])
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running[key]--
+			running[""]--
+			mu.Unlock()
+			return &JobResult{Success: true}
+		}}
+	}
+	results := wp.Run(context.Background(), jobs)
+
+	for i, result := range results {
+		if result == nil || !result.Success {
+			t.Errorf("results[%d] = %v, want success", i, result)
+		}
+	}
+	if highest["a"] != 1 || highest["b"] != 1 {
+		t.Errorf("max concurrent jobs by key = %v, want 1 for each key", highest)
+	}
+	if highest[""] != 2 {
+		t.Errorf("max concurrent jobs = %d, want 2", highest[""])
+	}
+}
+
+func TestWorkerPool_Run_Canceled(t *testing.T) {
+	var buf bytes.Buffer
+	wp := NewWorkerPool(&buf, WithWorkers(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first job is interrupted; the running job finishes and no other
+	// job starts
+	jobs := []Job{
+		{ID: 0, Func: func(ctx context.Context) *JobResult {
+			cancel()
+			return &JobResult{Success: true, Interrupted: ctx.Err() != nil}
+		}},
+		{ID: 1, Func: func(context.Context) *JobResult { return &JobResult{Success: true} }},
+		{ID: 2, Func: func(context.Context) *JobResult { return &JobResult{Success: true} }},
+	}
+	results := wp.Run(ctx, jobs)
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].NotStarted || !results[0].Interrupted {
+		t.Errorf("results[0] = %+v, want the job run and interrupted", results[0])
+	}
+	for _, result := range results[1:] {
+		if !result.NotStarted {
+			t.Errorf("results[%d] = %+v, want NotStarted", result.ID, result)
+		}
+	}
+}
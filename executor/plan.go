@@ -0,0 +1,595 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/tnagatomi/gh-fuda/api"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+// Command identifies the command a plan was computed for
+type Command string
+
+const (
+	CommandCreate Command = "create"
+	CommandDelete Command = "delete"
+	CommandSync   Command = "sync"
+	CommandEmpty  Command = "empty"
+	CommandMerge  Command = "merge"
+	CommandRename Command = "rename"
+)
+
+// listsLabels returns true if planning the command lists the labels of every
+// repository
+func (c Command) listsLabels() bool {
+	return c == CommandSync || c == CommandEmpty || c == CommandRename
+}
+
+// OperationType identifies the kind of change an operation makes
+type OperationType string
+
+const (
+	OperationCreate  OperationType = "create"
+	OperationUpdate  OperationType = "update"
+	OperationRename  OperationType = "rename"
+	OperationDelete  OperationType = "delete"
+	OperationRelabel OperationType = "relabel"
+	// OperationUnchanged records an existing label that already matches the
+	// desired one and needs no API call
+	OperationUnchanged OperationType = "unchanged"
+	// OperationSkip records a deletion that is not made because the label is
+	// still attached to items
+	OperationSkip OperationType = "skip"
+)
+
+// SyncMode selects which operations sync plans
+type SyncMode string
+
+const (
+	// SyncModeFull creates, updates and deletes labels
+	SyncModeFull SyncMode = "full"
+	// SyncModeAdditive creates and updates labels but never deletes them
+	SyncModeAdditive SyncMode = "additive"
+	// SyncModeUpdateOnly only updates labels that already exist
+	SyncModeUpdateOnly SyncMode = "update-only"
+	// SyncModePrune only deletes labels that are not in the set
+	SyncModePrune SyncMode = "prune"
+)
+
+// SyncModes lists the valid sync modes
+var SyncModes = []SyncMode{SyncModeFull, SyncModeAdditive, SyncModeUpdateOnly, SyncModePrune}
+
+// ParseSyncMode returns the sync mode named s
+func ParseSyncMode(s string) (SyncMode, error) {
+	for _, mode := range SyncModes {
+		if string(mode) == s {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("invalid sync mode %q: must be one of full, additive, update-only, prune", s)
+}
+
+// creates returns true if the mode creates missing labels
+func (m SyncMode) creates() bool {
+	return m == SyncModeFull || m == SyncModeAdditive
+}
+
+// updates returns true if the mode updates existing labels
+func (m SyncMode) updates() bool {
+	return m != SyncModePrune
+}
+
+// deletes returns true if the mode deletes labels not in the set
+func (m SyncMode) deletes() bool {
+	return m == SyncModeFull || m == SyncModePrune
+}
+
+// Operation is a single planned change to a repository.
+//
+// Before is the observed state of the label (nil when it does not exist yet)
+// and After is the desired state (nil when the label is deleted). A rename is
+// an update whose After carries a different name than Before. For relabel
+// operations Before is the source label and After the target label of Item.
+type Operation struct {
+	Type   OperationType     `json:"type"`
+	Before *option.Label     `json:"before,omitempty"`
+	After  *option.Label     `json:"after,omitempty"`
+	Item   *option.Labelable `json:"item,omitempty"`
+	// Usage is the number of items the deleted or skipped label was attached
+	// to when the plan was made, if it was counted
+	Usage *option.LabelUsage `json:"usage,omitempty"`
+	// Force makes a create fall back to an update if the label turns out to
+	// exist when the plan is applied.
+	Force bool `json:"force,omitempty"`
+}
+
+// RepoPlan is the ordered list of operations planned for a repository
+type RepoPlan struct {
+	Repo option.Repo `json:"repo"`
+	// Labels is the observed state of the repository's labels, or nil if
+	// the planner did not need to look at them
+	Labels     []option.Label `json:"labels"`
+	Operations []Operation    `json:"operations"`
+	// LabelIDs holds the node IDs of labels resolved while planning. Only
+	// merges need them, to relabel items.
+	LabelIDs map[string]option.GraphQLID `json:"label_ids,omitempty"`
+	// Err is set when the repository could not be planned
+	Err error `json:"-"`
+}
+
+// Plan is the set of operations a command would run across repositories
+type Plan struct {
+	Command Command     `json:"command"`
+	Repos   []*RepoPlan `json:"repos"`
+	// Excluded lists the selected repositories left out by the exclusions
+	Excluded []option.Repo `json:"excluded,omitempty"`
+}
+
+// HasErrors returns true if any repository could not be planned
+func (p *Plan) HasErrors() bool {
+	for _, rp := range p.Repos {
+		if rp.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// planError describes the step that failed while planning a repository
+type planError struct {
+	msg string
+	err error
+}
+
+func (e *planError) Error() string {
+	return fmt.Sprintf("%s: %v", e.msg, e.err)
+}
+
+func (e *planError) Unwrap() error {
+	return e.err
+}
+
+// buildPlan plans every repository in parallel and returns the plans in input
+// order, after expanding wildcards in repos. It returns ErrInterrupted if ctx
+// is canceled before every repository is planned.
+func (e *Executor) buildPlan(ctx context.Context, out io.Writer, command Command, repos []option.Repo, planRepo func(context.Context, option.Repo) *RepoPlan) (*Plan, error) {
+	repos, excluded, err := e.resolveRepos(ctx, repos)
+	if err != nil {
+		return nil, err
+	}
+
+	// Planning these commands lists the labels of every repository, so they
+	// are listed beforehand, several repositories per request
+	if command.listsLabels() || e.planPath != "" {
+		e.prefetchLabels(ctx, repos)
+		defer e.clearPrefetched()
+	}
+
+	wp := e.newWorkerPool(out)
+	plan := &Plan{
+		Command:  command,
+		Repos:    make([]*RepoPlan, len(repos)),
+		Excluded: excluded,
+	}
+	jobs := make([]Job, len(repos))
+
+	for i, repo := range repos {
+		jobs[i] = Job{
+			ID:  i,
+			Key: e.ownerKey(repo),
+			Func: func(ctx context.Context) *JobResult {
+				rp := planRepo(ctx, repo)
+				// A saved plan records the labels of every repository so
+				// that apply can detect changes made since
+				if e.planPath != "" && rp.Err == nil && rp.Labels == nil {
+					e.observeLabels(ctx, rp)
+				}
+				plan.Repos[i] = rp
+				return &JobResult{Success: rp.Err == nil}
+			},
+		}
+	}
+
+	wp.Run(ctx, jobs)
+	wp.ClearProgress()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%w while planning, before any label was changed", ErrInterrupted)
+	}
+
+	return plan, nil
+}
+
+// observeLabels records the current labels of the repository in the plan
+func (e *Executor) observeLabels(ctx context.Context, rp *RepoPlan) {
+	labels, err := e.listLabels(ctx, rp.Repo)
+	if err != nil {
+		rp.Err = &planError{msg: fmt.Sprintf("Failed to list labels for repository %q", rp.Repo), err: err}
+		return
+	}
+	rp.Labels = observed(labels)
+}
+
+// observed returns a non-nil copy of labels, so that a repository without
+// labels is distinguishable from one whose labels were never fetched
+func observed(labels []option.Label) []option.Label {
+	return append([]option.Label{}, labels...)
+}
+
+func (e *Executor) planCreate(ctx context.Context, repo option.Repo, labels []option.Label, force bool) *RepoPlan {
+	rp := &RepoPlan{Repo: repo}
+
+	var existingLabels []option.Label
+	if force {
+		var err error
+		existingLabels, err = e.listLabels(ctx, repo)
+		if err != nil {
+			rp.Err = &planError{msg: fmt.Sprintf("Failed to list labels for repository %q", repo), err: err}
+			return rp
+		}
+		rp.Labels = observed(existingLabels)
+	}
+
+	for _, label := range labels {
+		if existing, ok := findLabel(label.Name, existingLabels); ok {
+			rp.Operations = append(rp.Operations, updateOperation(existing, label))
+			continue
+		}
+		rp.Operations = append(rp.Operations, Operation{Type: OperationCreate, After: &label, Force: force})
+	}
+
+	return rp
+}
+
+// updateOperation returns the operation bringing existing to label. Label
+// names are case-insensitive, so a name differing only in case is renamed in
+// place, which keeps the label on its issues, pull requests and discussions.
+func updateOperation(existing, label option.Label) Operation {
+	if existing.Name != label.Name {
+		return Operation{Type: OperationRename, Before: &existing, After: &label}
+	}
+	if sameColor(existing.Color, label.Color) && sameDescription(existing.Description, label.Description) {
+		return Operation{Type: OperationUnchanged, Before: &existing, After: &label}
+	}
+	return Operation{Type: OperationUpdate, Before: &existing, After: &label}
+}
+
+// sameColor returns true if a and b are the same hex color, regardless of
+// case, a leading '#' or the 3-digit shorthand
+func sameColor(a, b string) bool {
+	return normalizeColor(a) == normalizeColor(b)
+}
+
+// sameDescription returns true if the desired description leaves the existing
+// one as it is. An empty desired description keeps the existing one, as
+// updating a label without a description does not clear it.
+func sameDescription(existing, desired string) bool {
+	return desired == "" || existing == desired
+}
+
+// normalizeColor returns color as 6 lowercase hex digits without a leading '#'
+func normalizeColor(color string) string {
+	color = strings.ToLower(strings.TrimPrefix(color, "#"))
+	if len(color) == 3 {
+		color = string([]byte{color[0], color[0], color[1], color[1], color[2], color[2]})
+	}
+	return color
+}
+
+func (e *Executor) planDelete(ctx context.Context, repo option.Repo, labels []string) *RepoPlan {
+	rp := &RepoPlan{Repo: repo}
+	for _, name := range labels {
+		rp.Operations = append(rp.Operations, Operation{Type: OperationDelete, Before: &option.Label{Name: name}})
+	}
+	return rp
+}
+
+func (e *Executor) planSync(ctx context.Context, repo option.Repo, labels []option.Label, mode SyncMode, deleteUsed bool) *RepoPlan {
+	rp := &RepoPlan{Repo: repo}
+
+	existingLabels, err := e.listLabels(ctx, repo)
+	if err != nil {
+		rp.Err = &planError{msg: fmt.Sprintf("Failed to list labels for repository %q", repo), err: err}
+		return rp
+	}
+	rp.Labels = observed(existingLabels)
+
+	if e.syncScope.IsSet() {
+		labels = slices.DeleteFunc(slices.Clone(labels), func(label option.Label) bool {
+			return !e.syncScope.Match(label)
+		})
+	}
+
+	matches := matchExisting(labels, existingLabels)
+
+	// Delete labels not in the new set, unless they are still in use. Labels
+	// outside the sync scope are left alone.
+	for i, existing := range existingLabels {
+		if !mode.deletes() || matched(i, matches) {
+			continue
+		}
+		if !e.syncScope.Match(existing) {
+			continue
+		}
+		usage, err := e.apiFor(repo).GetLabelUsage(ctx, repo, existing.Name)
+		if err != nil {
+			rp.Err = &planError{msg: fmt.Sprintf("Failed to count items with label %q in repository %q", existing.Name, repo), err: err}
+			return rp
+		}
+		if usage.Total() > 0 && !deleteUsed {
+			rp.Operations = append(rp.Operations, Operation{Type: OperationSkip, Before: &existing, Usage: &usage})
+			continue
+		}
+		rp.Operations = append(rp.Operations, Operation{Type: OperationDelete, Before: &existing, Usage: &usage})
+	}
+
+	// Create, update or rename labels
+	for i, label := range labels {
+		if j := matches[i]; j >= 0 {
+			if mode.updates() {
+				rp.Operations = append(rp.Operations, updateOperation(existingLabels[j], label))
+			}
+		} else if mode.creates() {
+			rp.Operations = append(rp.Operations, Operation{Type: OperationCreate, After: &label})
+		}
+	}
+
+	return rp
+}
+
+// matchExisting returns, for each label, the index of the existing label it
+// corresponds to, or -1 if there is none. A label matches an existing label
+// of the same name first; otherwise it takes over the first existing label
+// named after one of its aliases that no other label claimed.
+func matchExisting(labels, existing []option.Label) []int {
+	matches := make([]int, len(labels))
+	claimed := make(map[int]bool)
+
+	for i, label := range labels {
+		matches[i] = indexOfLabel(label.Name, existing)
+		if matches[i] >= 0 {
+			claimed[matches[i]] = true
+		}
+	}
+
+	for i, label := range labels {
+		if matches[i] >= 0 {
+			continue
+		}
+		for _, alias := range label.Aliases {
+			if j := indexOfLabel(alias, existing); j >= 0 && !claimed[j] {
+				matches[i] = j
+				claimed[j] = true
+				break
+			}
+		}
+	}
+
+	return matches
+}
+
+// matched returns true if the existing label at index i appears in matches
+func matched(i int, matches []int) bool {
+	for _, j := range matches {
+		if i == j {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Executor) planEmpty(ctx context.Context, repo option.Repo) *RepoPlan {
+	rp := &RepoPlan{Repo: repo}
+
+	labels, err := e.listLabels(ctx, repo)
+	if err != nil {
+		rp.Err = &planError{msg: fmt.Sprintf("Failed to list labels for repository %q", repo), err: err}
+		return rp
+	}
+	rp.Labels = observed(labels)
+
+	for _, label := range labels {
+		rp.Operations = append(rp.Operations, Operation{Type: OperationDelete, Before: &label})
+	}
+
+	return rp
+}
+
+func (e *Executor) planMerge(ctx context.Context, repo option.Repo, fromLabel, toLabel string) *RepoPlan {
+	rp := &RepoPlan{Repo: repo}
+
+	// Get source label ID
+	fromLabelID, err := e.apiFor(repo).GetLabelID(ctx, repo, fromLabel)
+	if err != nil {
+		rp.Err = &planError{msg: fmt.Sprintf("Failed to find source label %q in repository %q", fromLabel, repo), err: err}
+		return rp
+	}
+
+	// Get target label ID
+	toLabelID, err := e.apiFor(repo).GetLabelID(ctx, repo, toLabel)
+	if err != nil {
+		rp.Err = &planError{msg: fmt.Sprintf("Failed to find target label %q in repository %q", toLabel, repo), err: err}
+		return rp
+	}
+
+	// Search for items with source label
+	labelables, err := e.apiFor(repo).SearchLabelables(ctx, repo, fromLabel)
+	if err != nil {
+		rp.Err = &planError{msg: fmt.Sprintf("Failed to search for items with label %q in repository %q", fromLabel, repo), err: err}
+		return rp
+	}
+
+	rp.LabelIDs = map[string]option.GraphQLID{
+		fromLabel: fromLabelID,
+		toLabel:   toLabelID,
+	}
+
+	from := &option.Label{Name: fromLabel}
+	to := &option.Label{Name: toLabel}
+	for _, item := range labelables {
+		rp.Operations = append(rp.Operations, Operation{Type: OperationRelabel, Before: from, After: to, Item: &item})
+	}
+	rp.Operations = append(rp.Operations, Operation{Type: OperationDelete, Before: from})
+
+	return rp
+}
+
+func (e *Executor) planRename(ctx context.Context, repo option.Repo, fromLabel, toLabel, color, description string) *RepoPlan {
+	existingLabels, err := e.listLabels(ctx, repo)
+	if err != nil {
+		return &RepoPlan{Repo: repo, Err: &planError{msg: fmt.Sprintf("Failed to list labels for repository %q", repo), err: err}}
+	}
+
+	source, ok := findLabel(fromLabel, existingLabels)
+	if !ok {
+		err := &api.NotFoundError{ResourceType: api.ResourceTypeLabel}
+		return &RepoPlan{Repo: repo, Err: &planError{msg: fmt.Sprintf("Failed to find source label %q in repository %q", fromLabel, repo), err: err}}
+	}
+
+	// A case-only change renames the label itself; any other existing target
+	// is merged into, as two labels cannot share a name
+	target, exists := findLabel(toLabel, existingLabels)
+	if exists && !strings.EqualFold(fromLabel, toLabel) {
+		rp := e.planMerge(ctx, repo, source.Name, target.Name)
+		if rp.Err != nil {
+			return rp
+		}
+		rp.Labels = observed(existingLabels)
+		if color != "" || description != "" {
+			after := target
+			if color != "" {
+				after.Color = color
+			}
+			if description != "" {
+				after.Description = description
+			}
+			rp.Operations = append([]Operation{{Type: OperationUpdate, Before: &target, After: &after}}, rp.Operations...)
+		}
+		return rp
+	}
+
+	after := source
+	after.Name = toLabel
+	if color != "" {
+		after.Color = color
+	}
+	if description != "" {
+		after.Description = description
+	}
+
+	return &RepoPlan{
+		Repo:       repo,
+		Labels:     observed(existingLabels),
+		Operations: []Operation{{Type: OperationRename, Before: &source, After: &after}},
+	}
+}
+
+// Print writes a preview of the plan and returns an error if any repository could not be planned
+func (p *Plan) Print(out io.Writer) error {
+	for _, repo := range p.Excluded {
+		_, _ = fmt.Fprintf(out, "Would skip repository %q: excluded\n", repo)
+	}
+
+	for _, rp := range p.Repos {
+		if rp.Err != nil {
+			_, _ = fmt.Fprintf(out, "%v\n", rp.Err)
+			continue
+		}
+
+		if rp.isMerge() && !rp.hasOperation(OperationRelabel) {
+			_, _ = fmt.Fprintf(out, "No items found with label %q in repository %q\n", rp.sourceLabel(), rp.Repo)
+		}
+
+		preposition := rp.deletePreposition()
+
+		for _, op := range rp.Operations {
+			switch op.Type {
+			case OperationCreate:
+				_, _ = fmt.Fprintf(out, "Would create label %q for repository %q\n", op.After, rp.Repo)
+			case OperationUpdate:
+				_, _ = fmt.Fprintf(out, "Would update label %q for repository %q\n", op.After, rp.Repo)
+			case OperationRename:
+				_, _ = fmt.Fprintf(out, "Would rename label %q to %q for repository %q\n", op.Before.Name, op.After.Name, rp.Repo)
+			case OperationDelete:
+				if op.inUse() {
+					_, _ = fmt.Fprintf(out, "Would delete label %q %s repository %q (used by %s)\n", op.Before.Name, preposition, rp.Repo, op.Usage)
+					continue
+				}
+				_, _ = fmt.Fprintf(out, "Would delete label %q %s repository %q\n", op.Before.Name, preposition, rp.Repo)
+			case OperationSkip:
+				_, _ = fmt.Fprintf(out, "Would skip deleting label %q %s repository %q: used by %s\n", op.Before.Name, preposition, rp.Repo, op.Usage)
+			case OperationRelabel:
+				_, _ = fmt.Fprintf(out, "Would add label %q to %s #%d in repository %q\n", op.After.Name, op.Item.Type, op.Item.Number, rp.Repo)
+				_, _ = fmt.Fprintf(out, "Would remove label %q from %s #%d in repository %q\n", op.Before.Name, op.Item.Type, op.Item.Number, rp.Repo)
+			}
+		}
+	}
+
+	if p.HasErrors() {
+		return fmt.Errorf("some operations failed")
+	}
+	return nil
+}
+
+// inUse returns true if the label of the operation was attached to any item
+func (op Operation) inUse() bool {
+	return op.Usage != nil && op.Usage.Total() > 0
+}
+
+// isMerge returns true if the plan merges a label into another one
+func (rp *RepoPlan) isMerge() bool {
+	return rp.LabelIDs != nil
+}
+
+// deletePreposition returns how label deletions relate to the repository in
+// messages: a merge removes the label "from" it, everything else acts "for" it.
+func (rp *RepoPlan) deletePreposition() string {
+	if rp.isMerge() {
+		return "from"
+	}
+	return "for"
+}
+
+// hasOperation returns true if the plan contains an operation of the given type
+func (rp *RepoPlan) hasOperation(t OperationType) bool {
+	for _, op := range rp.Operations {
+		if op.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceLabel returns the name of the label a merge plan deletes
+func (rp *RepoPlan) sourceLabel() string {
+	for _, op := range rp.Operations {
+		if op.Type == OperationDelete {
+			return op.Before.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,466 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tnagatomi/gh-fuda/internal/mock"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+func TestPlanSync(t *testing.T) {
+	repo := option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}
+	existing := []option.Label{
+		{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+		{Name: "question", Color: "d876e3"},
+	}
+	m := &mock.MockAPI{
+		ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+			return existing, nil
+		},
+	}
+	e := &Executor{api: m}
+
+	got := e.planSync(context.Background(), repo, []option.Label{
+		{Name: "bug", Color: "ff0000", Description: "This is a bug"},
+		{Name: "enhancement", Color: "00ff00"},
+	}, SyncModeFull, false)
+
+	want := &RepoPlan{
+		Repo:   repo,
+		Labels: existing,
+		Operations: []Operation{
+			{Type: OperationDelete, Before: &option.Label{Name: "question", Color: "d876e3"}, Usage: &option.LabelUsage{}},
+			{
+				Type:   OperationUpdate,
+				Before: &option.Label{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+				After:  &option.Label{Name: "bug", Color: "ff0000", Description: "This is a bug"},
+			},
+			{Type: OperationCreate, After: &option.Label{Name: "enhancement", Color: "00ff00"}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("planSync() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPlanSync_EmptyDescription(t *testing.T) {
+	repo := option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}
+	existing := []option.Label{
+		{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+		{Name: "docs", Color: "0075ca", Description: "Improvements or additions to documentation"},
+	}
+	m := &mock.MockAPI{
+		ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+			return existing, nil
+		},
+		// Like the API, an update without a description keeps the current one
+		ApplyLabelChangesFunc: func(repo option.Repo, changes []option.LabelChange) []error {
+			for _, change := range changes {
+				for i := range existing {
+					if existing[i].Name != change.Label.Name {
+						continue
+					}
+					existing[i].Color = change.Label.Color
+					if change.Label.Description != "" {
+						existing[i].Description = change.Label.Description
+					}
+				}
+			}
+			return make([]error, len(changes))
+		},
+	}
+	e := &Executor{api: m}
+	labels := []option.Label{
+		{Name: "bug", Color: "ff0000"},
+		{Name: "docs", Color: "0075ca"},
+	}
+
+	first := e.planSync(context.Background(), repo, labels, SyncModeFull, false)
+	want := []Operation{
+		{
+			Type:   OperationUpdate,
+			Before: &option.Label{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+			After:  &option.Label{Name: "bug", Color: "ff0000"},
+		},
+		{
+			Type:   OperationUnchanged,
+			Before: &option.Label{Name: "docs", Color: "0075ca", Description: "Improvements or additions to documentation"},
+			After:  &option.Label{Name: "docs", Color: "0075ca"},
+		},
+	}
+	if diff := cmp.Diff(want, first.Operations); diff != "" {
+		t.Fatalf("planSync() operations mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := e.Apply(context.Background(), &bytes.Buffer{}, &Plan{Command: CommandSync, Repos: []*RepoPlan{first}}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	second := e.planSync(context.Background(), repo, labels, SyncModeFull, false)
+	for _, op := range second.Operations {
+		if op.Type != OperationUnchanged {
+			t.Errorf("planSync() after Apply() = %s %q, want unchanged", op.Type, op.Before.Name)
+		}
+	}
+}
+
+func TestPlanSync_Modes(t *testing.T) {
+	repo := option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}
+	existing := []option.Label{
+		{Name: "bug", Color: "d73a4a"},
+		{Name: "question", Color: "d876e3"},
+	}
+	labels := []option.Label{
+		{Name: "bug", Color: "ff0000"},
+		{Name: "enhancement", Color: "00ff00"},
+	}
+	deleteQuestion := Operation{Type: OperationDelete, Before: &option.Label{Name: "question", Color: "d876e3"}, Usage: &option.LabelUsage{}}
+	updateBug := Operation{Type: OperationUpdate, Before: &option.Label{Name: "bug", Color: "d73a4a"}, After: &option.Label{Name: "bug", Color: "ff0000"}}
+	createEnhancement := Operation{Type: OperationCreate, After: &option.Label{Name: "enhancement", Color: "00ff00"}}
+
+	tests := []struct {
+		mode SyncMode
+		want []Operation
+	}{
+		{mode: SyncModeFull, want: []Operation{deleteQuestion, updateBug, createEnhancement}},
+		{mode: SyncModeAdditive, want: []Operation{updateBug, createEnhancement}},
+		{mode: SyncModeUpdateOnly, want: []Operation{updateBug}},
+		{mode: SyncModePrune, want: []Operation{deleteQuestion}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			m := &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return existing, nil
+				},
+			}
+			e := &Executor{api: m}
+
+			got := e.planSync(context.Background(), repo, labels, tt.mode, false)
+			if diff := cmp.Diff(tt.want, got.Operations); diff != "" {
+				t.Errorf("planSync() operations mismatch (-want +got):\n%s", diff)
+			}
+			if !tt.mode.deletes() && len(m.GetLabelUsageCalls) > 0 {
+				t.Errorf("planSync() counted label usage in mode %q", tt.mode)
+			}
+		})
+	}
+}
+
+func TestPlanSync_Scope(t *testing.T) {
+	repo := option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}
+	existing := []option.Label{
+		{Name: "area/api", Color: "1d76db"},
+		{Name: "Area/legacy", Color: "1d76db"},
+		{Name: "bug", Color: "d73a4a"},
+		{Name: "areas", Color: "ededed"},
+	}
+	m := &mock.MockAPI{
+		ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+			return existing, nil
+		},
+	}
+	e := &Executor{api: m}
+	e.SetSyncScope(option.PrefixScope("area/"))
+
+	got := e.planSync(context.Background(), repo, []option.Label{
+		{Name: "area/api", Color: "0052cc"},
+		{Name: "area/ui", Color: "1d76db"},
+		{Name: "enhancement", Color: "a2eeef"},
+	}, SyncModeFull, false)
+
+	want := []Operation{
+		{Type: OperationDelete, Before: &option.Label{Name: "Area/legacy", Color: "1d76db"}, Usage: &option.LabelUsage{}},
+		{Type: OperationUpdate, Before: &option.Label{Name: "area/api", Color: "1d76db"}, After: &option.Label{Name: "area/api", Color: "0052cc"}},
+		{Type: OperationCreate, After: &option.Label{Name: "area/ui", Color: "1d76db"}},
+	}
+	if diff := cmp.Diff(want, got.Operations); diff != "" {
+		t.Errorf("planSync() operations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSameColor(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "ff0000", b: "ff0000", want: true},
+		{a: "FF0000", b: "ff0000", want: true},
+		{a: "f00", b: "ff0000", want: true},
+		{a: "#F00", b: "ff0000", want: true},
+		{a: "f00", b: "f00000", want: false},
+		{a: "ff0000", b: "00ff00", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := sameColor(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameColor(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseSyncMode(t *testing.T) {
+	for _, mode := range SyncModes {
+		got, err := ParseSyncMode(string(mode))
+		if err != nil || got != mode {
+			t.Errorf("ParseSyncMode(%q) = %q, %v", mode, got, err)
+		}
+	}
+	if _, err := ParseSyncMode("everything"); err == nil {
+		t.Errorf("ParseSyncMode() should fail for an unknown mode")
+	}
+}
+
+func TestPlanSync_Aliases(t *testing.T) {
+	repo := option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}
+	tests := []struct {
+		name     string
+		existing []option.Label
+		labels   []option.Label
+		want     []Operation
+	}{
+		{
+			name:     "existing label with alias name is renamed",
+			existing: []option.Label{{Name: "type/bug", Color: "d73a4a"}},
+			labels:   []option.Label{{Name: "kind: bug", Color: "ff0000", Aliases: []string{"type/bug"}}},
+			want: []Operation{
+				{
+					Type:   OperationRename,
+					Before: &option.Label{Name: "type/bug", Color: "d73a4a"},
+					After:  &option.Label{Name: "kind: bug", Color: "ff0000", Aliases: []string{"type/bug"}},
+				},
+			},
+		},
+		{
+			name:     "first matching alias wins",
+			existing: []option.Label{{Name: "bug"}, {Name: "type/bug"}},
+			labels:   []option.Label{{Name: "kind: bug", Aliases: []string{"type/bug", "bug"}}},
+			want: []Operation{
+				{Type: OperationDelete, Before: &option.Label{Name: "bug"}, Usage: &option.LabelUsage{}},
+				{
+					Type:   OperationRename,
+					Before: &option.Label{Name: "type/bug"},
+					After:  &option.Label{Name: "kind: bug", Aliases: []string{"type/bug", "bug"}},
+				},
+			},
+		},
+		{
+			name:     "alias is ignored when the label already exists",
+			existing: []option.Label{{Name: "kind: bug"}, {Name: "type/bug"}},
+			labels:   []option.Label{{Name: "kind: bug", Aliases: []string{"type/bug"}}},
+			want: []Operation{
+				{Type: OperationDelete, Before: &option.Label{Name: "type/bug"}, Usage: &option.LabelUsage{}},
+				{
+					Type:   OperationUnchanged,
+					Before: &option.Label{Name: "kind: bug"},
+					After:  &option.Label{Name: "kind: bug", Aliases: []string{"type/bug"}},
+				},
+			},
+		},
+		{
+			name:     "alias claimed by another label's name is not renamed",
+			existing: []option.Label{{Name: "bug"}},
+			labels: []option.Label{
+				{Name: "kind: bug", Aliases: []string{"bug"}},
+				{Name: "bug"},
+			},
+			want: []Operation{
+				{Type: OperationCreate, After: &option.Label{Name: "kind: bug", Aliases: []string{"bug"}}},
+				{Type: OperationUnchanged, Before: &option.Label{Name: "bug"}, After: &option.Label{Name: "bug"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return tt.existing, nil
+				},
+			}
+			e := &Executor{api: m}
+
+			got := e.planSync(context.Background(), repo, tt.labels, SyncModeFull, false)
+			if diff := cmp.Diff(tt.want, got.Operations); diff != "" {
+				t.Errorf("planSync() operations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPlanSync_UsedLabels(t *testing.T) {
+	repo := option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}
+	usage := option.LabelUsage{Issues: 3, Discussions: 1}
+	tests := []struct {
+		name       string
+		deleteUsed bool
+		want       []Operation
+	}{
+		{
+			name:       "used label is skipped",
+			deleteUsed: false,
+			want: []Operation{
+				{Type: OperationSkip, Before: &option.Label{Name: "wontfix"}, Usage: &usage},
+				{Type: OperationDelete, Before: &option.Label{Name: "question"}, Usage: &option.LabelUsage{}},
+			},
+		},
+		{
+			name:       "used label is deleted with deleteUsed",
+			deleteUsed: true,
+			want: []Operation{
+				{Type: OperationDelete, Before: &option.Label{Name: "wontfix"}, Usage: &usage},
+				{Type: OperationDelete, Before: &option.Label{Name: "question"}, Usage: &option.LabelUsage{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return []option.Label{{Name: "wontfix"}, {Name: "question"}}, nil
+				},
+				GetLabelUsageFunc: func(repo option.Repo, labelName string) (option.LabelUsage, error) {
+					if labelName == "wontfix" {
+						return usage, nil
+					}
+					return option.LabelUsage{}, nil
+				},
+			}
+			e := &Executor{api: m}
+
+			got := e.planSync(context.Background(), repo, nil, SyncModeFull, tt.deleteUsed)
+			if diff := cmp.Diff(tt.want, got.Operations); diff != "" {
+				t.Errorf("planSync() operations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPlanSync_UsageError(t *testing.T) {
+	repo := option.Repo{Owner: "tnagatomi", Repo: "mock-repo"}
+	m := &mock.MockAPI{
+		ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+			return []option.Label{{Name: "question"}}, nil
+		},
+		GetLabelUsageFunc: func(repo option.Repo, labelName string) (option.LabelUsage, error) {
+			return option.LabelUsage{}, errors.New("boom")
+		},
+	}
+	e := &Executor{api: m}
+
+	got := e.planSync(context.Background(), repo, nil, SyncModeFull, false)
+	if got.Err == nil || got.Err.Error() != `Failed to count items with label "question" in repository "tnagatomi/mock-repo": boom` {
+		t.Errorf("planSync() error = %v", got.Err)
+	}
+}
+
+func TestPlanMerge(t *testing.T) {
+	repo := option.Repo{Owner: "owner", Repo: "repo"}
+	m := &mock.MockAPI{
+		GetLabelIDFunc: func(repo option.Repo, labelName string) (option.GraphQLID, error) {
+			return option.GraphQLID("LA_" + labelName), nil
+		},
+		SearchLabelablesFunc: func(repo option.Repo, labelName string) ([]option.Labelable, error) {
+			return []option.Labelable{
+				{ID: "I_1", Number: 1, Title: "Issue 1", Type: option.LabelableTypeIssue},
+			}, nil
+		},
+	}
+	e := &Executor{api: m}
+
+	got := e.planMerge(context.Background(), repo, "old", "new")
+
+	want := &RepoPlan{
+		Repo: repo,
+		Operations: []Operation{
+			{
+				Type:   OperationRelabel,
+				Before: &option.Label{Name: "old"},
+				After:  &option.Label{Name: "new"},
+				Item:   &option.Labelable{ID: "I_1", Number: 1, Title: "Issue 1", Type: option.LabelableTypeIssue},
+			},
+			{Type: OperationDelete, Before: &option.Label{Name: "old"}},
+		},
+		LabelIDs: map[string]option.GraphQLID{"old": "LA_old", "new": "LA_new"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("planMerge() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPlan_Print_MergeWithoutItems(t *testing.T) {
+	plan := &Plan{
+		Command: CommandMerge,
+		Repos: []*RepoPlan{
+			{
+				Repo:       option.Repo{Owner: "owner", Repo: "repo"},
+				Operations: []Operation{{Type: OperationDelete, Before: &option.Label{Name: "old"}}},
+				LabelIDs:   map[string]option.GraphQLID{"old": "LA_old", "new": "LA_new"},
+			},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := plan.Print(out); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	want := `No items found with label "old" in repository "owner/repo"
+Would delete label "old" from repository "owner/repo"
+`
+	if out.String() != want {
+		t.Errorf("Print() got = %q, want %q", out.String(), want)
+	}
+}
+
+func TestPlan_Print_Excluded(t *testing.T) {
+	plan := &Plan{
+		Command: CommandDelete,
+		Repos: []*RepoPlan{
+			{
+				Repo:       option.Repo{Owner: "owner", Repo: "repo"},
+				Operations: []Operation{{Type: OperationDelete, Before: &option.Label{Name: "old"}}},
+			},
+		},
+		Excluded: []option.Repo{{Owner: "owner", Repo: "legacy-app"}},
+	}
+
+	out := &bytes.Buffer{}
+	if err := plan.Print(out); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	want := `Would skip repository "owner/legacy-app": excluded
+Would delete label "old" for repository "owner/repo"
+`
+	if out.String() != want {
+		t.Errorf("Print() got = %q, want %q", out.String(), want)
+	}
+}
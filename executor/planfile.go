@@ -0,0 +1,211 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+// planFileVersion is the version of the plan file format
+const planFileVersion = 1
+
+// planFile is the on-disk representation of a plan
+type planFile struct {
+	Version int `json:"version"`
+	*Plan
+}
+
+// WritePlan saves the plan as JSON to path
+func WritePlan(path string, plan *Plan) error {
+	data, err := json.MarshalIndent(planFile{Version: planFileVersion, Plan: plan}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write plan file: %v", err)
+	}
+	return nil
+}
+
+// ReadPlan loads a plan saved by WritePlan
+func ReadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %v", err)
+	}
+
+	pf := planFile{Plan: &Plan{}}
+	if err := json.Unmarshal(data, &pf); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file: %v", err)
+	}
+	if pf.Version != planFileVersion {
+		return nil, fmt.Errorf("unsupported plan file version: %d", pf.Version)
+	}
+
+	for i, rp := range pf.Repos {
+		if rp == nil || rp.Labels == nil {
+			return nil, fmt.Errorf("repository at index %d has no recorded labels", i)
+		}
+		for j, op := range rp.Operations {
+			if err := op.validate(); err != nil {
+				return nil, fmt.Errorf("invalid operation %d for repository %q: %v", j, rp.Repo, err)
+			}
+		}
+	}
+
+	return pf.Plan, nil
+}
+
+// validate checks that the operation carries the labels its type needs
+func (op Operation) validate() error {
+	switch op.Type {
+	case OperationCreate:
+		if op.After == nil {
+			return fmt.Errorf("create operation has no label")
+		}
+	case OperationUpdate, OperationRename, OperationUnchanged:
+		if op.Before == nil || op.After == nil {
+			return fmt.Errorf("%s operation needs both before and after labels", op.Type)
+		}
+	case OperationDelete:
+		if op.Before == nil {
+			return fmt.Errorf("delete operation has no label")
+		}
+	case OperationSkip:
+		if op.Before == nil || op.Usage == nil {
+			return fmt.Errorf("skip operation needs a label and its usage")
+		}
+	case OperationRelabel:
+		if op.Before == nil || op.After == nil || op.Item == nil {
+			return fmt.Errorf("relabel operation needs source and target labels and an item")
+		}
+	default:
+		return fmt.Errorf("unknown operation type %q", op.Type)
+	}
+	return nil
+}
+
+// savePlan prints the plan and writes it to the plan path. Nothing is written
+// if any repository could not be planned.
+func (e *Executor) savePlan(out io.Writer, plan *Plan) error {
+	if err := plan.Print(out); err != nil {
+		return err
+	}
+	// Record the default host, so the plan is applied to the same repositories
+	// whatever the default host is then
+	if e.host != "" {
+		for _, rp := range plan.Repos {
+			if rp.Repo.Host == "" {
+				rp.Repo.Host = e.host
+			}
+		}
+	}
+	if err := WritePlan(e.planPath, plan); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Saved plan to %s\n", e.planPath)
+	return nil
+}
+
+// ApplySaved applies a plan loaded from a plan file. It refuses to run if the
+// labels of any repository changed since the plan was made.
+func (e *Executor) ApplySaved(ctx context.Context, out io.Writer, plan *Plan) error {
+	for _, rp := range plan.Repos {
+		rp.Repo = e.localRepo(rp.Repo)
+		if err := e.connect(rp.Repo); err != nil {
+			return err
+		}
+	}
+
+	wp := e.newWorkerPool(out)
+	stale := make([]string, len(plan.Repos))
+	jobs := make([]Job, len(plan.Repos))
+
+	for i, rp := range plan.Repos {
+		jobs[i] = Job{
+			ID:  i,
+			Key: e.ownerKey(rp.Repo),
+			Func: func(ctx context.Context) *JobResult {
+				current, err := e.apiFor(rp.Repo).ListLabels(ctx, rp.Repo)
+				if err != nil {
+					stale[i] = fmt.Sprintf("Failed to list labels for repository %q: %v", rp.Repo, err)
+				} else if !sameLabels(rp.Labels, current) {
+					stale[i] = fmt.Sprintf("Labels for repository %q changed since the plan was made", rp.Repo)
+				}
+				return &JobResult{Success: stale[i] == ""}
+			},
+		}
+	}
+
+	wp.Run(ctx, jobs)
+	wp.ClearProgress()
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w before any label was changed", ErrInterrupted)
+	}
+
+	var isStale bool
+	for _, msg := range stale {
+		if msg != "" {
+			_, _ = fmt.Fprintln(out, msg)
+			isStale = true
+		}
+	}
+	if isStale {
+		return fmt.Errorf("plan is stale, create a new one")
+	}
+
+	if e.dryRun {
+		return plan.Print(out)
+	}
+	return e.Apply(ctx, out, plan)
+}
+
+// sameLabels reports whether two label sets are identical regardless of order.
+// Colors are compared case-insensitively.
+func sameLabels(a, b []option.Label) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sorted := func(labels []option.Label) []option.Label {
+		s := append([]option.Label{}, labels...)
+		sort.Slice(s, func(i, j int) bool { return s[i].Name < s[j].Name })
+		return s
+	}
+	sa, sb := sorted(a), sorted(b)
+
+	for i := range sa {
+		if sa[i].Name != sb[i].Name ||
+			!sameColor(sa[i].Color, sb[i].Color) ||
+			sa[i].Description != sb[i].Description {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package executor
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tnagatomi/gh-fuda/internal/mock"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+func TestWritePlan_ReadPlan_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	want := &Plan{
+		Command: CommandMerge,
+		Repos: []*RepoPlan{
+			{
+				Repo:   option.Repo{Owner: "owner", Repo: "repo"},
+				Labels: []option.Label{{Name: "old", Color: "ffffff"}, {Name: "new", Color: "000000", Description: "New"}},
+				Operations: []Operation{
+					{
+						Type:   OperationRelabel,
+						Before: &option.Label{Name: "old"},
+						After:  &option.Label{Name: "new"},
+						Item:   &option.Labelable{ID: "I_1", Number: 1, Title: "Issue 1", Type: option.LabelableTypeIssue},
+					},
+					{Type: OperationDelete, Before: &option.Label{Name: "old"}},
+				},
+				LabelIDs: map[string]option.GraphQLID{"old": "LA_old", "new": "LA_new"},
+			},
+		},
+	}
+
+	if err := WritePlan(path, want); err != nil {
+		t.Fatalf("WritePlan() error = %v", err)
+	}
+	got, err := ReadPlan(path)
+	if err != nil {
+		t.Fatalf("ReadPlan() error = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReadPlan() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadPlan_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		errContains string
+	}{
+		{
+			name:        "unsupported version",
+			content:     `{"version": 2, "command": "sync", "repos": []}`,
+			errContains: "unsupported plan file version",
+		},
+		{
+			name:        "missing observed labels",
+			content:     `{"version": 1, "command": "sync", "repos": [{"repo": {"owner": "o", "repo": "r"}, "operations": []}]}`,
+			errContains: "has no recorded labels",
+		},
+		{
+			name:        "update without before state",
+			content:     `{"version": 1, "command": "sync", "repos": [{"repo": {"owner": "o", "repo": "r"}, "labels": [], "operations": [{"type": "update", "after": {"name": "bug"}}]}]}`,
+			errContains: "update operation needs both before and after labels",
+		},
+		{
+			name:        "unknown operation",
+			content:     `{"version": 1, "command": "sync", "repos": [{"repo": {"owner": "o", "repo": "r"}, "labels": [], "operations": [{"type": "transfer"}]}]}`,
+			errContains: `unknown operation type "transfer"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := ReadPlan(path)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("ReadPlan() error = %v, want error containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestSavePlan_RecordsLabels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	m := &mock.MockAPI{
+		ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+			return []option.Label{{Name: "bug", Color: "ff0000"}}, nil
+		},
+	}
+	e := &Executor{api: m, planPath: path}
+
+	out := &bytes.Buffer{}
+	if err := e.Delete(context.Background(), out, []option.Repo{{Owner: "owner", Repo: "repo"}}, []string{"bug"}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(m.DeleteLabelCalls) != 0 {
+		t.Errorf("Delete() with plan path deleted labels: %v", m.DeleteLabelCalls)
+	}
+
+	plan, err := ReadPlan(path)
+	if err != nil {
+		t.Fatalf("ReadPlan() error = %v", err)
+	}
+	want := []option.Label{{Name: "bug", Color: "ff0000"}}
+	if diff := cmp.Diff(want, plan.Repos[0].Labels); diff != "" {
+		t.Errorf("recorded labels mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestApplySaved(t *testing.T) {
+	repo := option.Repo{Owner: "owner", Repo: "repo"}
+	plan := &Plan{
+		Command: CommandSync,
+		Repos: []*RepoPlan{
+			{
+				Repo:       repo,
+				Labels:     []option.Label{{Name: "bug", Color: "FF0000"}},
+				Operations: []Operation{{Type: OperationDelete, Before: &option.Label{Name: "bug", Color: "FF0000"}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		current    []option.Label
+		wantErr    bool
+		wantDelete int
+	}{
+		{
+			name:       "unchanged labels are applied",
+			current:    []option.Label{{Name: "bug", Color: "ff0000"}},
+			wantDelete: 1,
+		},
+		{
+			name:    "changed labels refuse to apply",
+			current: []option.Label{{Name: "bug", Color: "00ff00"}},
+			wantErr: true,
+		},
+		{
+			name:    "added label refuses to apply",
+			current: []option.Label{{Name: "bug", Color: "ff0000"}, {Name: "new"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mock.MockAPI{
+				ListLabelsFunc: func(repo option.Repo) ([]option.Label, error) {
+					return tt.current, nil
+				},
+			}
+			e := &Executor{api: m}
+
+			out := &bytes.Buffer{}
+			err := e.ApplySaved(context.Background(), out, plan)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplySaved() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(out.String(), "changed since the plan was made") {
+				t.Errorf("ApplySaved() output = %q, want stale repository reported", out.String())
+			}
+			if len(m.DeleteLabelCalls) != tt.wantDelete {
+				t.Errorf("ApplySaved() delete calls = %d, want %d", len(m.DeleteLabelCalls), tt.wantDelete)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package mock
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/tnagatomi/gh-fuda/option"
 )
@@ -27,11 +29,38 @@ type MockAPI struct {
 		Repo  option.Repo
 	}
 
+	ApplyLabelChangesFunc  func(repo option.Repo, changes []option.LabelChange) []error
+	ApplyLabelChangesCalls []struct {
+		Repo    option.Repo
+		Changes []option.LabelChange
+	}
+
 	ListLabelsFunc  func(repo option.Repo) ([]option.Label, error)
 	ListLabelsCalls []struct {
 		Repo option.Repo
 	}
 
+	ListLabelsOfReposFunc  func(repos []option.Repo) ([][]option.Label, []error)
+	ListLabelsOfReposCalls []struct {
+		Repos []option.Repo
+	}
+
+	GetLabelUsageFunc  func(repo option.Repo, labelName string) (option.LabelUsage, error)
+	GetLabelUsageCalls []struct {
+		Repo      option.Repo
+		LabelName string
+	}
+
+	ListRepositoriesFunc  func(owner string) ([]option.Repository, error)
+	ListRepositoriesCalls []struct {
+		Owner string
+	}
+
+	SearchRepositoriesFunc  func(query string) ([]option.Repository, error)
+	SearchRepositoriesCalls []struct {
+		Query string
+	}
+
 	GetRepositoryIDFunc  func(repo option.Repo) (option.GraphQLID, error)
 	GetRepositoryIDCalls []struct {
 		Repo option.Repo
@@ -60,9 +89,11 @@ type MockAPI struct {
 		LabelableID option.GraphQLID
 		LabelIDs    []option.GraphQLID
 	}
+
+	RateLimitDelayFunc func() time.Duration
 }
 
-func (m *MockAPI) CreateLabel(label option.Label, repo option.Repo) error {
+func (m *MockAPI) CreateLabel(ctx context.Context, label option.Label, repo option.Repo) error {
 	m.mu.Lock()
 	m.CreateLabelCalls = append(m.CreateLabelCalls, struct {
 		Label option.Label
@@ -77,7 +108,7 @@ func (m *MockAPI) CreateLabel(label option.Label, repo option.Repo) error {
 	return nil
 }
 
-func (m *MockAPI) UpdateLabel(label option.Label, repo option.Repo) error {
+func (m *MockAPI) UpdateLabel(ctx context.Context, label option.Label, repo option.Repo) error {
 	m.mu.Lock()
 	m.UpdateLabelCalls = append(m.UpdateLabelCalls, struct {
 		Label option.Label
@@ -92,7 +123,7 @@ func (m *MockAPI) UpdateLabel(label option.Label, repo option.Repo) error {
 	return nil
 }
 
-func (m *MockAPI) DeleteLabel(label string, repo option.Repo) error {
+func (m *MockAPI) DeleteLabel(ctx context.Context, label string, repo option.Repo) error {
 	m.mu.Lock()
 	m.DeleteLabelCalls = append(m.DeleteLabelCalls, struct {
 		Label string
@@ -107,7 +138,36 @@ func (m *MockAPI) DeleteLabel(label string, repo option.Repo) error {
 	return nil
 }
 
-func (m *MockAPI) ListLabels(repo option.Repo) ([]option.Label, error) {
+// ApplyLabelChanges makes each change with CreateLabel, UpdateLabel or
+// DeleteLabel unless ApplyLabelChangesFunc is set, so that tests of single
+// changes also cover batches
+func (m *MockAPI) ApplyLabelChanges(ctx context.Context, repo option.Repo, changes []option.LabelChange) []error {
+	m.mu.Lock()
+	m.ApplyLabelChangesCalls = append(m.ApplyLabelChangesCalls, struct {
+		Repo    option.Repo
+		Changes []option.LabelChange
+	}{repo, changes})
+	m.mu.Unlock()
+
+	if m.ApplyLabelChangesFunc != nil {
+		return m.ApplyLabelChangesFunc(repo, changes)
+	}
+
+	errs := make([]error, len(changes))
+	for i, change := range changes {
+		switch change.Type {
+		case option.LabelChangeCreate:
+			errs[i] = m.CreateLabel(ctx, change.Label, repo)
+		case option.LabelChangeUpdate:
+			errs[i] = m.UpdateLabel(ctx, change.Label, repo)
+		case option.LabelChangeDelete:
+			errs[i] = m.DeleteLabel(ctx, change.Label.Name, repo)
+		}
+	}
+	return errs
+}
+
+func (m *MockAPI) ListLabels(ctx context.Context, repo option.Repo) ([]option.Label, error) {
 	m.mu.Lock()
 	m.ListLabelsCalls = append(m.ListLabelsCalls, struct {
 		Repo option.Repo
@@ -121,7 +181,71 @@ func (m *MockAPI) ListLabels(repo option.Repo) ([]option.Label, error) {
 	return nil, nil
 }
 
-func (m *MockAPI) GetRepositoryID(repo option.Repo) (option.GraphQLID, error) {
+// ListLabelsOfRepos lists the labels of each repository with ListLabels
+// unless ListLabelsOfReposFunc is set
+func (m *MockAPI) ListLabelsOfRepos(ctx context.Context, repos []option.Repo) ([][]option.Label, []error) {
+	m.mu.Lock()
+	m.ListLabelsOfReposCalls = append(m.ListLabelsOfReposCalls, struct {
+		Repos []option.Repo
+	}{repos})
+	m.mu.Unlock()
+
+	if m.ListLabelsOfReposFunc != nil {
+		return m.ListLabelsOfReposFunc(repos)
+	}
+
+	labels := make([][]option.Label, len(repos))
+	errs := make([]error, len(repos))
+	for i, repo := range repos {
+		labels[i], errs[i] = m.ListLabels(ctx, repo)
+	}
+	return labels, errs
+}
+
+func (m *MockAPI) GetLabelUsage(ctx context.Context, repo option.Repo, labelName string) (option.LabelUsage, error) {
+	m.mu.Lock()
+	m.GetLabelUsageCalls = append(m.GetLabelUsageCalls, struct {
+		Repo      option.Repo
+		LabelName string
+	}{repo, labelName})
+	m.mu.Unlock()
+
+	if m.GetLabelUsageFunc != nil {
+		return m.GetLabelUsageFunc(repo, labelName)
+	}
+
+	return option.LabelUsage{}, nil
+}
+
+func (m *MockAPI) ListRepositories(ctx context.Context, owner string) ([]option.Repository, error) {
+	m.mu.Lock()
+	m.ListRepositoriesCalls = append(m.ListRepositoriesCalls, struct {
+		Owner string
+	}{owner})
+	m.mu.Unlock()
+
+	if m.ListRepositoriesFunc != nil {
+		return m.ListRepositoriesFunc(owner)
+	}
+
+	return nil, nil
+}
+
+func (m *MockAPI) SearchRepositories(ctx context.Context, query string) ([]option.Repository, error) {
+	m.mu.Lock()
+	m.SearchRepositoriesCalls = append(m.SearchRepositoriesCalls, struct {
+		Query string
+	}{query})
+	m.mu.Unlock()
+
+	if m.SearchRepositoriesFunc != nil {
+		return m.SearchRepositoriesFunc(query)
+	}
+
+	return nil, nil
+}
+
+func (m *MockAPI) GetRepositoryID(ctx context.Context, repo option.Repo) (option.GraphQLID, error) {
 	m.mu.Lock()
 	m.GetRepositoryIDCalls = append(m.GetRepositoryIDCalls, struct {
 		Repo option.Repo
@@ -135,7 +259,7 @@ func (m *MockAPI) GetRepositoryID(repo option.Repo) (option.GraphQLID, error) {
 	return "", nil
 }
 
-func (m *MockAPI) GetLabelID(repo option.Repo, labelName string) (option.GraphQLID, error) {
+func (m *MockAPI) GetLabelID(ctx context.Context, repo option.Repo, labelName string) (option.GraphQLID, error) {
 	m.mu.Lock()
 	m.GetLabelIDCalls = append(m.GetLabelIDCalls, struct {
 		Repo      option.Repo
@@ -150,7 +274,7 @@ func (m *MockAPI) GetLabelID(repo option.Repo, labelName string) (option.GraphQL
 	return "", nil
 }
 
-func (m *MockAPI) SearchLabelables(repo option.Repo, labelName string) ([]option.Labelable, error) {
+func (m *MockAPI) SearchLabelables(ctx context.Context, repo option.Repo, labelName string) ([]option.Labelable, error) {
 	m.mu.Lock()
 	m.SearchLabelablesCalls = append(m.SearchLabelablesCalls, struct {
 		Repo      option.Repo
@@ -165,7 +289,7 @@ func (m *MockAPI) SearchLabelables(repo option.Repo, labelName string) ([]option
 	return nil, nil
 }
 
-func (m *MockAPI) AddLabelsToLabelable(labelableID option.GraphQLID, labelIDs []option.GraphQLID) error {
+func (m *MockAPI) AddLabelsToLabelable(ctx context.Context, labelableID option.GraphQLID, labelIDs []option.GraphQLID) error {
 	m.mu.Lock()
 	m.AddLabelsToLabelableCalls = append(m.AddLabelsToLabelableCalls, struct {
 		LabelableID option.GraphQLID
@@ -180,7 +304,7 @@ func (m *MockAPI) AddLabelsToLabelable(labelableID option.GraphQLID, labelIDs []
 	return nil
 }
 
-func (m *MockAPI) RemoveLabelsFromLabelable(labelableID option.GraphQLID, labelIDs []option.GraphQLID) error {
+func (m *MockAPI) RemoveLabelsFromLabelable(ctx context.Context, labelableID option.GraphQLID, labelIDs []option.GraphQLID) error {
 	m.mu.Lock()
 	m.RemoveLabelsFromLabelableCalls = append(m.RemoveLabelsFromLabelableCalls, struct {
 		LabelableID option.GraphQLID
@@ -194,3 +318,11 @@ func (m *MockAPI) RemoveLabelsFromLabelable(labelableID option.GraphQLID, labelI
 
 	return nil
 }
+
+func (m *MockAPI) RateLimitDelay() time.Duration {
+	if m.RateLimitDelayFunc != nil {
+		return m.RateLimitDelayFunc()
+	}
+
+	return 0
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package option
+
+// Config describes named label sets and the repositories each one goes to,
+// as read from a .fuda.yaml file
+type Config struct {
+	// Mode is the sync mode, empty for the default
+	Mode string
+	// DeleteUsed deletes labels not in a repository's set even if they are
+	// still in use
+	DeleteUsed bool
+	// Exclude leaves repositories out of every target
+	Exclude RepoPatterns
+	// LabelSets are the label sets by name
+	LabelSets map[string][]Label
+	// Targets select repositories and the labels they get. A repository
+	// selected by several targets gets the labels of all of them, in order.
+	Targets []ConfigTarget
+}
+
+// ConfigTarget gives label sets, and labels of its own, to a group of
+// repositories
+type ConfigTarget struct {
+	// Repos are the repositories of the target, OWNER/* selecting every
+	// repository of an owner
+	Repos []Repo
+	// Query is a repository search query selecting more repositories
+	Query string
+	// Topics and Language narrow down the repositories OWNER/* and Query
+	// expand to, like RepoFilter
+	Topics   []string
+	Language string
+	// Sets are the names of the label sets the repositories get
+	Sets []string
+	// Labels are extra labels, or overrides of labels of the sets with the
+	// same name, where empty fields keep the values of the set
+	Labels []Label
+}
@@ -21,12 +21,159 @@ THE SOFTWARE.
 */
 package option
 
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
 type Label struct {
-	Name        string
-	Color       string
-	Description string
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+	// Aliases are previous names of the label. Sync renames an existing label
+	// with one of these names instead of deleting it and creating a new one.
+	Aliases []string `json:"aliases,omitempty"`
+	// NewName renames the label when it is updated. Empty keeps the current name.
+	NewName string `json:"new_name,omitempty"`
+	// Group is the label group the label was defined in, nil if none
+	Group *LabelGroup `json:"-"`
 }
 
 func (l Label) String() string {
 	return l.Name
 }
+
+// LabelGroup is a group of labels named with a common prefix, such as area/api
+// and area/ui in the group area
+type LabelGroup struct {
+	Name      string
+	Separator string
+}
+
+// DefaultGroupSeparator separates the group name from the names of its labels
+// when a group does not set a separator
+const DefaultGroupSeparator = "/"
+
+// Prefix returns the prefix of the names of the labels of the group
+func (g LabelGroup) Prefix() string {
+	return g.Name + g.Separator
+}
+
+// GroupPrefix returns the name prefix of the labels of the group named name,
+// as defined by labels, or the name with the default separator if none of the
+// labels was defined in that group
+func GroupPrefix(labels []Label, name string) string {
+	for _, label := range labels {
+		if label.Group != nil && strings.EqualFold(label.Group.Name, name) {
+			return label.Group.Prefix()
+		}
+	}
+	return name + DefaultGroupSeparator
+}
+
+// ErrNoLabelsToSync is returned by CheckSyncLabels
+var ErrNoLabelsToSync = errors.New("no labels to sync")
+
+// CheckSyncLabels returns ErrNoLabelsToSync if labels is empty. Syncing no
+// labels would delete every unused label of the repositories, or of the sync
+// scope, which is never what is meant.
+func CheckSyncLabels(labels []Label) error {
+	if len(labels) == 0 {
+		return ErrNoLabelsToSync
+	}
+	return nil
+}
+
+// LabelScope selects the labels an operation manages by name. The zero value
+// selects every label.
+type LabelScope struct {
+	// Pattern is the scope as given, such as team-a/* or /^team-a\//
+	Pattern string
+	// Include are glob patterns, one of which the names of the labels in the
+	// scope match, if any
+	Include []string
+	// Exclude are glob patterns the names of the labels in the scope match
+	// none of
+	Exclude []string
+	// Regexp matches the names of the labels in the scope, if set
+	Regexp *regexp.Regexp
+}
+
+// PrefixScope returns the scope of the labels whose name starts with prefix,
+// ignoring case as GitHub does for label names
+func PrefixScope(prefix string) LabelScope {
+	return LabelScope{
+		Pattern: prefix + "*",
+		Regexp:  regexp.MustCompile("(?i)^" + regexp.QuoteMeta(prefix)),
+	}
+}
+
+// IsSet returns true if the scope leaves out some labels
+func (s LabelScope) IsSet() bool {
+	return len(s.Include) > 0 || len(s.Exclude) > 0 || s.Regexp != nil
+}
+
+// Match returns true if the label is in the scope
+func (s LabelScope) Match(l Label) bool {
+	matchName := func(pattern string) bool { return MatchGlob(pattern, l.Name) }
+	if s.Regexp != nil && !s.Regexp.MatchString(l.Name) {
+		return false
+	}
+	if len(s.Include) > 0 && !slices.ContainsFunc(s.Include, matchName) {
+		return false
+	}
+	return !slices.ContainsFunc(s.Exclude, matchName)
+}
+
+// LabelUsage is the number of items a label is attached to
+type LabelUsage struct {
+	Issues       int `json:"issues"`
+	PullRequests int `json:"pull_requests"`
+	Discussions  int `json:"discussions"`
+}
+
+// Total returns the number of items of any type the label is attached to
+func (u LabelUsage) Total() int {
+	return u.Issues + u.PullRequests + u.Discussions
+}
+
+func (u LabelUsage) String() string {
+	var parts []string
+	for _, c := range []struct {
+		n    int
+		noun string
+	}{
+		{u.Issues, "issue"},
+		{u.PullRequests, "pull request"},
+		{u.Discussions, "discussion"},
+	} {
+		if c.n == 1 {
+			parts = append(parts, fmt.Sprintf("1 %s", c.noun))
+		} else if c.n > 1 {
+			parts = append(parts, fmt.Sprintf("%d %ss", c.n, c.noun))
+		}
+	}
+	if len(parts) == 0 {
+		return "no items"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// LabelChangeType is the kind of change a LabelChange makes
+type LabelChangeType string
+
+const (
+	LabelChangeCreate LabelChangeType = "create"
+	LabelChangeUpdate LabelChangeType = "update"
+	LabelChangeDelete LabelChangeType = "delete"
+)
+
+// LabelChange is a change to a label of a repository. An update looks the
+// label up by Name and renames it to NewName if set, a delete only uses Name.
+type LabelChange struct {
+	Type  LabelChangeType
+	Label Label
+}
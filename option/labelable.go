@@ -37,10 +37,10 @@ const (
 )
 
 type Labelable struct {
-	ID     GraphQLID // GraphQL node ID
-	Number int
-	Title  string
-	Type   LabelableType
+	ID     GraphQLID     `json:"id"` // GraphQL node ID
+	Number int           `json:"number"`
+	Title  string        `json:"title"`
+	Type   LabelableType `json:"type"`
 }
 
 func (l Labelable) String() string {
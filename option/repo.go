@@ -21,11 +21,136 @@ THE SOFTWARE.
 */
 package option
 
+import (
+	"slices"
+	"strings"
+)
+
+// RepoWildcard as the repository name selects every repository of the owner
+const RepoWildcard = "*"
+
 type Repo struct {
-	Owner string
-	Repo  string
+	// Host is the GitHub host of the repository, such as a GitHub Enterprise
+	// Server hostname. Empty means the default host.
+	Host  string `json:"host,omitempty"`
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
 }
 
 func (r Repo) String() string {
+	if r.Host != "" {
+		return r.Host + "/" + r.Owner + "/" + r.Repo
+	}
 	return r.Owner + "/" + r.Repo
 }
+
+// IsWildcard returns true if the repo selects every repository of its owner
+func (r Repo) IsWildcard() bool {
+	return r.Repo == RepoWildcard
+}
+
+// Repository is a repository with the attributes a RepoFilter looks at
+type Repository struct {
+	Repo
+	IsArchived       bool
+	IsFork           bool
+	IsTemplate       bool
+	HasIssuesEnabled bool
+	// Visibility is "public", "private" or "internal"
+	Visibility string
+	// Language is the primary language, empty if GitHub detected none
+	Language string
+	Topics   []string
+}
+
+// Visibilities are the visibilities of repositories, as GitHub reports them in
+// lowercase
+var Visibilities = []string{"public", "private", "internal"}
+
+// RepoFilter selects the repositories a wildcard expands to
+type RepoFilter struct {
+	SkipArchived       bool
+	SkipForks          bool
+	SkipTemplates      bool
+	SkipIssuesDisabled bool
+	// SkipVisibilities lists the visibilities to skip, among Visibilities
+	SkipVisibilities []string
+	// Topics keeps only repositories with all of these topics
+	Topics []string
+	// Language keeps only repositories with this primary language, ignoring case
+	Language string
+}
+
+// Match returns true if the filter keeps the repository
+func (f RepoFilter) Match(r Repository) bool {
+	switch {
+	case f.SkipArchived && r.IsArchived,
+		f.SkipForks && r.IsFork,
+		f.SkipTemplates && r.IsTemplate,
+		f.SkipIssuesDisabled && !r.HasIssuesEnabled,
+		slices.Contains(f.SkipVisibilities, r.Visibility),
+		f.Language != "" && !strings.EqualFold(f.Language, r.Language):
+		return false
+	}
+	for _, topic := range f.Topics {
+		if !slices.Contains(r.Topics, strings.ToLower(topic)) {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchQualifiers returns the repository search qualifiers selecting the
+// repositories with the topics and language of the filter, or "" if it selects
+// by neither
+func (f RepoFilter) SearchQualifiers() string {
+	var qualifiers []string
+	for _, topic := range f.Topics {
+		qualifiers = append(qualifiers, "topic:"+searchValue(topic))
+	}
+	if f.Language != "" {
+		qualifiers = append(qualifiers, "language:"+searchValue(f.Language))
+	}
+	return strings.Join(qualifiers, " ")
+}
+
+// searchValue quotes a search qualifier value with spaces, such as a language
+// like Jupyter Notebook
+func searchValue(s string) string {
+	if strings.ContainsAny(s, " \t") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// Narrows returns false if the filter selects by topic or language while repos
+// and query leave nothing to narrow down. Topics and languages only narrow
+// down selections of many repositories, the OWNER/* wildcards and the search
+// query, as checking them for named repositories would cost a request each.
+func (f RepoFilter) Narrows(repos []Repo, query string) bool {
+	if len(f.Topics) == 0 && f.Language == "" {
+		return true
+	}
+	return query != "" || slices.ContainsFunc(repos, Repo.IsWildcard)
+}
+
+// RepoPatterns are OWNER/REPO glob patterns, as matched by MatchGlob, such as
+// acme/legacy-* or */sandbox. OWNER/REPO patterns match repositories of any
+// host, HOST/OWNER/REPO patterns only those of the host.
+type RepoPatterns []string
+
+// Match returns true if the repository matches any of the patterns
+func (p RepoPatterns) Match(r Repo) bool {
+	name := r.Owner + "/" + r.Repo
+	full := r.String()
+	for _, pattern := range p {
+		target := name
+		if strings.Count(pattern, "/") == 2 {
+			target = full
+		}
+		if MatchGlob(pattern, target) {
+			return true
+		}
+	}
+	return false
+}
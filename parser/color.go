@@ -25,6 +25,7 @@ package parser
 import (
 	"crypto/sha256"
 	"fmt"
+	"strings"
 )
 
 // GenerateColor generates a deterministic 6-character hex color from a label name.
@@ -38,3 +39,13 @@ func GenerateColor(name string) string {
 	hash := sha256.Sum256([]byte(name))
 	return fmt.Sprintf("%02x%02x%02x", hash[0], hash[1], hash[2])
 }
+
+// Color validates a label color given as a 3 or 6 character hex code, with or
+// without a leading '#', and returns it without the '#'.
+func Color(input string) (string, error) {
+	color := strings.TrimPrefix(strings.TrimSpace(input), "#")
+	if !isHexColor(color) {
+		return "", fmt.Errorf("invalid color format: %s", input)
+	}
+	return color, nil
+}
@@ -99,3 +99,32 @@ func TestGenerateColor_DifferentNamesProduceDifferentColors(t *testing.T) {
 		colors[color] = name
 	}
 }
+
+func TestColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "6 characters", input: "a2eeef", want: "a2eeef"},
+		{name: "3 characters", input: "07c", want: "07c"},
+		{name: "leading hash", input: "#FF0000", want: "FF0000"},
+		{name: "invalid characters", input: "gggggg", wantErr: true},
+		{name: "invalid length", input: "ff", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Color(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Color() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Color() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
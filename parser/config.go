@@ -0,0 +1,174 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/tnagatomi/gh-fuda/option"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLConfig represents the YAML structure of a config file
+type YAMLConfig struct {
+	Mode       string                 `yaml:"mode"`
+	DeleteUsed bool                   `yaml:"delete_used"`
+	Exclude    []string               `yaml:"exclude"`
+	LabelSets  map[string][]yaml.Node `yaml:"label_sets"`
+	Targets    []YAMLConfigTarget     `yaml:"targets"`
+}
+
+// YAMLConfigTarget represents the YAML structure of a target of a config file
+type YAMLConfigTarget struct {
+	Repos    []string    `yaml:"repos"`
+	Query    string      `yaml:"query"`
+	Topics   []string    `yaml:"topics"`
+	Language string      `yaml:"language"`
+	Sets     []string    `yaml:"sets"`
+	Labels   []yaml.Node `yaml:"labels"`
+}
+
+// ConfigFromYAML parses a config file. Unknown keys are rejected, so that a
+// misspelled key does not silently change which labels repositories get.
+func ConfigFromYAML(path string) (*option.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	var yc YAMLConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&yc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse YAML: %v", err)
+	}
+
+	cfg := &option.Config{
+		Mode:       yc.Mode,
+		DeleteUsed: yc.DeleteUsed,
+		LabelSets:  make(map[string][]option.Label, len(yc.LabelSets)),
+	}
+
+	for _, e := range yc.Exclude {
+		pattern, err := parseRepoPattern(e)
+		if err != nil {
+			return nil, fmt.Errorf("exclude: %v", err)
+		}
+		cfg.Exclude = append(cfg.Exclude, pattern)
+	}
+
+	for name, nodes := range yc.LabelSets {
+		labels, err := nodesToLabels(nodes, true)
+		if err != nil {
+			return nil, fmt.Errorf("label set %q: %v", name, err)
+		}
+		if err := option.CheckSyncLabels(labels); err != nil {
+			return nil, fmt.Errorf("label set %q: %w", name, err)
+		}
+		cfg.LabelSets[name] = labels
+	}
+
+	if len(yc.Targets) == 0 {
+		return nil, errors.New("no targets defined")
+	}
+	for i, yt := range yc.Targets {
+		target, err := yamlToTarget(yt, cfg.LabelSets)
+		if err != nil {
+			return nil, fmt.Errorf("target at index %d: %v", i, err)
+		}
+		cfg.Targets = append(cfg.Targets, target)
+	}
+
+	return cfg, nil
+}
+
+// yamlToTarget validates a target, whose sets must be defined in labelSets
+func yamlToTarget(yt YAMLConfigTarget, labelSets map[string][]option.Label) (option.ConfigTarget, error) {
+	if len(yt.Repos) == 0 && yt.Query == "" {
+		return option.ConfigTarget{}, errors.New("repos or query must be specified")
+	}
+	if len(yt.Sets) == 0 && len(yt.Labels) == 0 {
+		return option.ConfigTarget{}, errors.New("sets or labels must be specified")
+	}
+
+	target := option.ConfigTarget{
+		Query:    yt.Query,
+		Topics:   yt.Topics,
+		Language: yt.Language,
+		Sets:     yt.Sets,
+	}
+
+	for _, r := range yt.Repos {
+		repo, err := parseRepo(r)
+		if err != nil {
+			return option.ConfigTarget{}, err
+		}
+		target.Repos = append(target.Repos, repo)
+	}
+
+	filter := option.RepoFilter{Topics: yt.Topics, Language: yt.Language}
+	if !filter.Narrows(target.Repos, yt.Query) {
+		return option.ConfigTarget{}, errors.New("topics and language need OWNER/* in repos, or a query")
+	}
+
+	for _, set := range yt.Sets {
+		if _, ok := labelSets[set]; !ok {
+			return option.ConfigTarget{}, fmt.Errorf("undefined label set %q", set)
+		}
+	}
+
+	// Colors are left empty, so that an override keeps the color of the set
+	if len(yt.Labels) > 0 {
+		labels, err := nodesToLabels(yt.Labels, false)
+		if err != nil {
+			return option.ConfigTarget{}, err
+		}
+		target.Labels = labels
+	}
+
+	return target, nil
+}
+
+// nodesToLabels decodes and validates a list of labels and label groups, like
+// yamlToLabels
+func nodesToLabels(nodes []yaml.Node, generateColors bool) ([]option.Label, error) {
+	ptrs := make([]*yaml.Node, len(nodes))
+	for i := range nodes {
+		ptrs[i] = &nodes[i]
+	}
+	yamlLabels, groups, _, err := decodeYAMLLabels(ptrs)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := yamlToLabels(yamlLabels, generateColors)
+	if err != nil {
+		return nil, err
+	}
+	for i := range labels {
+		labels[i].Group = groups[i]
+	}
+	return labels, nil
+}
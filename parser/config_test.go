@@ -0,0 +1,179 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+func TestConfigFromYAML(t *testing.T) {
+	tests := []struct {
+		name        string
+		yamlContent string
+		want        *option.Config
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "valid config",
+			yamlContent: `mode: additive
+exclude:
+  - acme/legacy-*
+label_sets:
+  common:
+    - name: bug
+      color: d73a4a
+    - name: question
+  frontend:
+    - name: ui
+      color: 0e8a16
+      description: User interface
+targets:
+  - repos: [acme/*]
+    sets: [common]
+  - repos: [acme/*]
+    topics: [frontend]
+    sets: [common, frontend]
+  - repos: [ghe.example.com/acme/web]
+    labels:
+      - name: bug
+        color: ff0000
+`,
+			want: &option.Config{
+				Mode:    "additive",
+				Exclude: option.RepoPatterns{"acme/legacy-*"},
+				LabelSets: map[string][]option.Label{
+					"common": {
+						{Name: "bug", Color: "d73a4a"},
+						{Name: "question", Color: GenerateColor("question")},
+					},
+					"frontend": {
+						{Name: "ui", Color: "0e8a16", Description: "User interface"},
+					},
+				},
+				Targets: []option.ConfigTarget{
+					{Repos: []option.Repo{{Owner: "acme", Repo: "*"}}, Sets: []string{"common"}},
+					{Repos: []option.Repo{{Owner: "acme", Repo: "*"}}, Topics: []string{"frontend"}, Sets: []string{"common", "frontend"}},
+					{Repos: []option.Repo{{Host: "ghe.example.com", Owner: "acme", Repo: "web"}}, Labels: []option.Label{{Name: "bug", Color: "ff0000"}}},
+				},
+			},
+		},
+		{
+			name: "unknown key",
+			yamlContent: `targets:
+  - repos: [acme/*]
+    set: [common]
+`,
+			wantErr:     true,
+			errContains: "field set not found",
+		},
+		{
+			name:        "no targets",
+			yamlContent: `label_sets: {}`,
+			wantErr:     true,
+			errContains: "no targets defined",
+		},
+		{
+			name: "undefined label set",
+			yamlContent: `targets:
+  - repos: [acme/*]
+    sets: [common]
+`,
+			wantErr:     true,
+			errContains: `target at index 0: undefined label set "common"`,
+		},
+		{
+			name: "empty label set",
+			yamlContent: `label_sets:
+  common: []
+targets:
+  - repos: [acme/*]
+    sets: [common]
+`,
+			wantErr:     true,
+			errContains: `label set "common": no labels to sync`,
+		},
+		{
+			name: "target without repositories",
+			yamlContent: `label_sets:
+  common: [{name: bug}]
+targets:
+  - sets: [common]
+`,
+			wantErr:     true,
+			errContains: "repos or query must be specified",
+		},
+		{
+			name: "topics without wildcard",
+			yamlContent: `label_sets:
+  common: [{name: bug}]
+targets:
+  - repos: [acme/web]
+    topics: [frontend]
+    sets: [common]
+`,
+			wantErr:     true,
+			errContains: "topics and language need OWNER/* in repos, or a query",
+		},
+		{
+			name: "invalid color",
+			yamlContent: `label_sets:
+  common: [{name: bug, color: red}]
+targets:
+  - repos: [acme/*]
+    sets: [common]
+`,
+			wantErr:     true,
+			errContains: `label set "common": label "bug" has invalid color format`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".fuda.yaml")
+			if err := os.WriteFile(path, []byte(tt.yamlContent), 0644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+
+			got, err := ConfigFromYAML(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConfigFromYAML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ConfigFromYAML() error = %v, want error containing %q", err, tt.errContains)
+				}
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ConfigFromYAML() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ package parser
 import (
 	"fmt"
 	"github.com/tnagatomi/gh-fuda/option"
+	"regexp"
 	"strings"
 )
 
@@ -77,6 +78,34 @@ func Label(input string) ([]option.Label, error) {
 	return labels, nil
 }
 
+// LabelScope parses the labels a sync manages, either a glob such as team-a/*,
+// as matched by option.MatchGlob, or a regular expression between slashes
+// such as /^team-a\//. Label names are case-insensitive on GitHub, so the
+// scope is too.
+func LabelScope(input string) (option.LabelScope, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return option.LabelScope{}, fmt.Errorf("label scope cannot be empty")
+	}
+
+	if len(input) < 2 || !strings.HasPrefix(input, "/") || !strings.HasSuffix(input, "/") {
+		if err := option.CheckGlob(input); err != nil {
+			return option.LabelScope{}, fmt.Errorf("invalid label scope %q: %v", input, err)
+		}
+		return option.LabelScope{Pattern: input, Include: []string{input}}, nil
+	}
+
+	expr := input[1 : len(input)-1]
+	if expr == "" {
+		return option.LabelScope{}, fmt.Errorf("label scope %q has an empty regular expression", input)
+	}
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return option.LabelScope{}, fmt.Errorf("invalid label scope %q: %v", input, err)
+	}
+	return option.LabelScope{Pattern: input, Regexp: re}, nil
+}
+
 func isHexColor(s string) bool {
 	length := len(s)
 	if length != 3 && length != 6 {
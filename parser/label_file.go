@@ -0,0 +1,326 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tnagatomi/gh-fuda/option"
+	"gopkg.in/yaml.v3"
+)
+
+// labelFormat is the format of a label file
+type labelFormat int
+
+const (
+	labelFormatYAML labelFormat = iota
+	labelFormatJSON
+)
+
+func (f labelFormat) String() string {
+	if f == labelFormatJSON {
+		return "JSON"
+	}
+	return "YAML"
+}
+
+// formatOf returns the format of a label file from its extension
+func formatOf(path string) labelFormat {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return labelFormatJSON
+	}
+	return labelFormatYAML
+}
+
+// labelDef is a label with the place it is defined in
+type labelDef struct {
+	label option.Label
+	file  string
+	// line is 0 if unknown
+	line int
+}
+
+func (d labelDef) pos() string {
+	return filePos(d.file, d.line)
+}
+
+func filePos(file string, line int) string {
+	if line > 0 {
+		return fmt.Sprintf("%s:%d", file, line)
+	}
+	return file
+}
+
+// labelFile is the content of a label file. It is either a list of labels, or
+// an object of the form
+//
+//	include: [base.yaml, team.json]
+//	remove: [wontfix]
+//	labels: [...]
+//
+// where include lists files whose labels are included, relative to the file,
+// remove drops included labels by name and labels adds labels of the file.
+// Later definitions override earlier ones with the same name.
+type labelFile struct {
+	include     []string
+	remove      []string
+	removeLines []int
+	labels      []labelDef
+}
+
+// labelsFromFile reads the labels of a label file in format, resolving its
+// includes
+func labelsFromFile(path string, format labelFormat) ([]option.Label, error) {
+	defs, err := loadLabelFile(filepath.Clean(path), format, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]option.Label, 0, len(defs))
+	for _, d := range defs {
+		labels = append(labels, d.label)
+	}
+	return labels, nil
+}
+
+// loadLabelFile reads the labels of a label file, resolving its includes.
+// stack holds the files including it, to detect include cycles.
+func loadLabelFile(path string, format labelFormat, stack []string) ([]labelDef, error) {
+	if i := slices.Index(stack, path); i >= 0 {
+		return nil, fmt.Errorf("include cycle: %s", strings.Join(append(stack[i:], path), " -> "))
+	}
+
+	f, err := readLabelFile(path, format)
+	if err != nil {
+		// Errors of the file given by the user are reported as before
+		if len(stack) > 0 {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return nil, err
+	}
+
+	var defs []labelDef
+	for _, include := range f.include {
+		if strings.Contains(include, "://") || filepath.IsAbs(include) {
+			return nil, fmt.Errorf("%s: include %q must be a path relative to the file", path, include)
+		}
+		includePath := filepath.Join(filepath.Dir(path), include)
+		included, err := loadLabelFile(includePath, formatOf(includePath), append(slices.Clip(stack), path))
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range included {
+			defs = setLabelDef(defs, d)
+		}
+	}
+
+	for i, name := range f.remove {
+		j := indexOfLabelDef(name, defs)
+		if j < 0 {
+			return nil, fmt.Errorf("%s: cannot remove label %q: it is not included", filePos(path, f.removeLines[i]), name)
+		}
+		defs = slices.Delete(defs, j, j+1)
+	}
+
+	for i, d := range f.labels {
+		if j := indexOfLabelDef(d.label.Name, f.labels[:i]); j >= 0 {
+			return nil, fmt.Errorf("%s: label %q conflicts with the definition at %s", d.pos(), d.label.Name, f.labels[j].pos())
+		}
+		defs = setLabelDef(defs, d)
+	}
+
+	return defs, nil
+}
+
+// setLabelDef adds d to defs, replacing the definition of a label with the
+// same name in place
+func setLabelDef(defs []labelDef, d labelDef) []labelDef {
+	if i := indexOfLabelDef(d.label.Name, defs); i >= 0 {
+		defs[i] = d
+		return defs
+	}
+	return append(defs, d)
+}
+
+// indexOfLabelDef returns the index of the definition of the label named
+// name, compared case-insensitively as GitHub does, or -1
+func indexOfLabelDef(name string, defs []labelDef) int {
+	return slices.IndexFunc(defs, func(d labelDef) bool {
+		return strings.EqualFold(d.label.Name, name)
+	})
+}
+
+// readLabelFile reads and validates a single label file, without resolving
+// its includes
+func readLabelFile(path string, format labelFormat) (*labelFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s file: %v", format, err)
+	}
+
+	if format == labelFormatJSON {
+		return parseJSONLabelFile(path, data)
+	}
+	return parseYAMLLabelFile(path, data)
+}
+
+// parseYAMLLabelFile parses a label file in YAML
+func parseYAMLLabelFile(path string, data []byte) (*labelFile, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %v", err)
+	}
+	f := &labelFile{}
+	if len(doc.Content) == 0 {
+		return f, nil
+	}
+
+	root := doc.Content[0]
+	var labelNodes []*yaml.Node
+	switch root.Kind {
+	case yaml.SequenceNode:
+		labelNodes = root.Content
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			key, value := root.Content[i], root.Content[i+1]
+			var err error
+			switch key.Value {
+			case "include":
+				err = value.Decode(&f.include)
+			case "remove":
+				err = value.Decode(&f.remove)
+				f.removeLines = nodeLines(value)
+			case "labels":
+				if value.Kind != yaml.SequenceNode {
+					err = errors.New("labels must be a list")
+				}
+				labelNodes = value.Content
+			default:
+				err = fmt.Errorf("unknown key %q (expected include, remove or labels)", key.Value)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse YAML: line %d: %v", key.Line, err)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("failed to parse YAML: line %d: expected a list of labels or an object with include, remove and labels", root.Line)
+	}
+
+	yamlLabels, groups, lines, err := decodeYAMLLabels(labelNodes)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := yamlToLabels(yamlLabels, true)
+	if err != nil {
+		return nil, err
+	}
+	for i, label := range labels {
+		label.Group = groups[i]
+		f.labels = append(f.labels, labelDef{label: label, file: path, line: lines[i]})
+	}
+
+	return f, nil
+}
+
+// parseJSONLabelFile parses a label file in JSON
+func parseJSONLabelFile(path string, data []byte) (*labelFile, error) {
+	f := &labelFile{}
+	var jsonLabels []JSONLabel
+
+	isObject := bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+	if isObject {
+		var obj struct {
+			Include []string    `json:"include"`
+			Remove  []string    `json:"remove"`
+			Labels  []JSONLabel `json:"labels"`
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&obj); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %v", err)
+		}
+		f.include = obj.Include
+		f.remove = obj.Remove
+		jsonLabels = obj.Labels
+	} else if err := json.Unmarshal(data, &jsonLabels); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+
+	labels, err := jsonToLabels(jsonLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	labelLines, removeLines := jsonLines(data, isObject)
+	f.removeLines = make([]int, len(f.remove))
+	copy(f.removeLines, removeLines)
+	for i, label := range labels {
+		var line int
+		if i < len(labelLines) {
+			line = labelLines[i]
+		}
+		f.labels = append(f.labels, labelDef{label: label, file: path, line: line})
+	}
+
+	return f, nil
+}
+
+// jsonLines returns the lines of the labels and of the removed labels of a
+// label file in JSON. As JSON is YAML, they are read from its YAML nodes. Tabs
+// can only be whitespace in valid JSON, but may not indent YAML, so they are
+// replaced by spaces first. Lines are missing if it cannot be read as YAML.
+func jsonLines(data []byte, isObject bool) (labelLines, removeLines []int) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(bytes.ReplaceAll(data, []byte("\t"), []byte(" ")), &doc); err != nil || len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	root := doc.Content[0]
+	if !isObject {
+		return nodeLines(root), nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		switch root.Content[i].Value {
+		case "labels":
+			labelLines = nodeLines(root.Content[i+1])
+		case "remove":
+			removeLines = nodeLines(root.Content[i+1])
+		}
+	}
+	return labelLines, removeLines
+}
+
+// nodeLines returns the lines of the items of a YAML sequence
+func nodeLines(node *yaml.Node) []int {
+	lines := make([]int, len(node.Content))
+	for i, item := range node.Content {
+		lines[i] = item.Line
+	}
+	return lines
+}
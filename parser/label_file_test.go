@@ -0,0 +1,198 @@
+/*
+Copyright © 2026 Takayuki Nagatomi <tnagatomi@okweird.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tnagatomi/gh-fuda/option"
+)
+
+func TestLabelFile_Composition(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		path        string
+		want        []option.Label
+		errContains string
+	}{
+		{
+			name: "include, override and remove",
+			files: map[string]string{
+				"base.yaml": `- name: bug
+  color: d73a4a
+- name: wontfix
+  color: ffffff
+- name: question
+  color: d876e3
+`,
+				"shared/extra.json": `[
+	{"name": "help wanted", "color": "008672"}
+]`,
+				"team.yaml": `include:
+  - base.yaml
+  - shared/extra.json
+remove:
+  - WONTFIX
+labels:
+  - name: Bug
+    color: ff0000
+    description: Something isn't working
+  - name: team/frontend
+    color: 0e8a16
+`,
+			},
+			path: "team.yaml",
+			want: []option.Label{
+				{Name: "Bug", Color: "ff0000", Description: "Something isn't working"},
+				{Name: "question", Color: "d876e3"},
+				{Name: "help wanted", Color: "008672"},
+				{Name: "team/frontend", Color: "0e8a16"},
+			},
+		},
+		{
+			name: "nested includes relative to the including file",
+			files: map[string]string{
+				"base/core.yaml":   `[{name: bug, color: d73a4a}]`,
+				"base/common.yaml": `{include: [core.yaml], labels: [{name: docs, color: 0075ca}]}`,
+				"team.json": `{
+  "include": ["base/common.yaml"],
+  "labels": [{"name": "bug", "color": "ee0701"}]
+}`,
+			},
+			path: "team.json",
+			want: []option.Label{
+				{Name: "bug", Color: "ee0701"},
+				{Name: "docs", Color: "0075ca"},
+			},
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"a.yaml":     `{include: [sub/b.yaml]}`,
+				"sub/b.yaml": `{include: [../a.yaml]}`,
+			},
+			path:        "a.yaml",
+			errContains: "include cycle: a.yaml -> sub/b.yaml -> a.yaml",
+		},
+		{
+			name: "conflicting definitions in a file",
+			files: map[string]string{
+				"labels.yaml": `labels:
+  - name: bug
+    color: d73a4a
+  - name: docs
+    color: 0075ca
+  - name: BUG
+    color: ff0000
+`,
+			},
+			path:        "labels.yaml",
+			errContains: `labels.yaml:6: label "BUG" conflicts with the definition at labels.yaml:2`,
+		},
+		{
+			name: "conflicting definitions in a JSON file",
+			files: map[string]string{
+				"labels.json": "[\n\t{\"name\": \"bug\"},\n\t{\"name\": \"bug\"}\n]",
+			},
+			path:        "labels.json",
+			errContains: `labels.json:3: label "bug" conflicts with the definition at labels.json:2`,
+		},
+		{
+			name: "removing a label that is not included",
+			files: map[string]string{
+				"base.yaml": `[{name: bug}]`,
+				"team.yaml": "include: [base.yaml]\nremove:\n  - wontfix\n",
+			},
+			path:        "team.yaml",
+			errContains: `team.yaml:3: cannot remove label "wontfix": it is not included`,
+		},
+		{
+			name: "URL include",
+			files: map[string]string{
+				"team.yaml": `{include: ["https://example.com/base.yaml"]}`,
+			},
+			path:        "team.yaml",
+			errContains: `include "https://example.com/base.yaml" must be a path relative to the file`,
+		},
+		{
+			name: "invalid included file",
+			files: map[string]string{
+				"base.yaml": `[{name: bug, color: red}]`,
+				"team.yaml": `{include: [base.yaml]}`,
+			},
+			path:        "team.yaml",
+			errContains: `base.yaml: label "bug" has invalid color format: red`,
+		},
+		{
+			name: "unknown key",
+			files: map[string]string{
+				"team.yaml": `{includes: [base.yaml]}`,
+			},
+			path:        "team.yaml",
+			errContains: `unknown key "includes"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Run from the directory, so that errors name files as in the test
+			t.Chdir(dir)
+
+			var got []option.Label
+			var err error
+			if strings.HasSuffix(tt.path, ".json") {
+				got, err = LabelFromJSON(tt.path)
+			} else {
+				got, err = LabelFromYAML(tt.path)
+			}
+
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("error = %v, want error containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("labels mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
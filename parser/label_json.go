@@ -25,30 +25,26 @@ package parser
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/tnagatomi/gh-fuda/option"
 )
 
 // JSONLabel represents the JSON structure for a label
 type JSONLabel struct {
-	Name        string `json:"name"`
-	Color       string `json:"color"`
-	Description string `json:"description"`
+	Name        string   `json:"name"`
+	Color       string   `json:"color"`
+	Description string   `json:"description"`
+	Aliases     []string `json:"aliases,omitempty"`
 }
 
-// LabelFromJSON parses labels from a JSON file
+// LabelFromJSON parses labels from a JSON file, either a list of labels or an
+// object composing labels of other files (see labelFile)
 func LabelFromJSON(path string) ([]option.Label, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read JSON file: %v", err)
-	}
-
-	var jsonLabels []JSONLabel
-	if err := json.Unmarshal(data, &jsonLabels); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %v", err)
-	}
+	return labelsFromFile(path, labelFormatJSON)
+}
 
+// jsonToLabels validates labels read from JSON, generating missing colors
+func jsonToLabels(jsonLabels []JSONLabel) ([]option.Label, error) {
 	var labels []option.Label
 	for i, jl := range jsonLabels {
 		if jl.Name == "" {
@@ -64,12 +60,38 @@ func LabelFromJSON(path string) ([]option.Label, error) {
 			color = GenerateColor(jl.Name)
 		}
 
+		for _, alias := range jl.Aliases {
+			if alias == "" {
+				return nil, fmt.Errorf("label %q has an empty alias", jl.Name)
+			}
+		}
+
 		labels = append(labels, option.Label{
 			Name:        jl.Name,
 			Color:       color,
 			Description: jl.Description,
+			Aliases:     jl.Aliases,
 		})
 	}
 
 	return labels, nil
 }
+
+// LabelToJSON formats labels in the format read by LabelFromJSON
+func LabelToJSON(labels []option.Label) ([]byte, error) {
+	jsonLabels := make([]JSONLabel, 0, len(labels))
+	for _, label := range labels {
+		jsonLabels = append(jsonLabels, JSONLabel{
+			Name:        label.Name,
+			Color:       label.Color,
+			Description: label.Description,
+			Aliases:     label.Aliases,
+		})
+	}
+
+	data, err := json.MarshalIndent(jsonLabels, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format JSON: %v", err)
+	}
+	return append(data, '\n'), nil
+}
@@ -25,6 +25,8 @@ package parser
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -151,6 +153,27 @@ func TestLabelFromJSON(t *testing.T) {
 			wantErr:     true,
 			errContains: "invalid color format",
 		},
+		{
+			name: "label with aliases",
+			jsonContent: `[
+				{
+					"name": "kind: bug",
+					"color": "d73a4a",
+					"aliases": ["type/bug", "bug"]
+				}
+			]`,
+			want: []option.Label{
+				{Name: "kind: bug", Color: "d73a4a", Aliases: []string{"type/bug", "bug"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "label with empty alias",
+			jsonContent: `[{"name": "bug", "aliases": [""]}]`,
+			want:        nil,
+			wantErr:     true,
+			errContains: "has an empty alias",
+		},
 		{
 			name:        "invalid JSON format",
 			jsonContent: `{"invalid": "json"}`,
@@ -197,7 +220,8 @@ func TestLabelFromJSON(t *testing.T) {
 			for i, label := range got {
 				if label.Name != tt.want[i].Name ||
 					label.Color != tt.want[i].Color ||
-					label.Description != tt.want[i].Description {
+					label.Description != tt.want[i].Description ||
+					!slices.Equal(label.Aliases, tt.want[i].Aliases) {
 					t.Errorf("LabelFromJSON() label[%d] = %+v, want %+v", i, label, tt.want[i])
 				}
 			}
@@ -214,3 +238,27 @@ func TestLabelFromJSON_FileNotFound(t *testing.T) {
 		t.Errorf("LabelFromJSON() error = %v, want error containing 'failed to read JSON file'", err)
 	}
 }
+
+func TestLabelToJSON_RoundTrip(t *testing.T) {
+	labels := []option.Label{
+		{Name: "kind: bug", Color: "d73a4a", Description: "Something isn't working", Aliases: []string{"bug"}},
+		{Name: "question", Color: "d876e3"},
+	}
+
+	data, err := LabelToJSON(labels)
+	if err != nil {
+		t.Fatalf("LabelToJSON() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "labels.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	got, err := LabelFromJSON(path)
+	if err != nil {
+		t.Fatalf("LabelFromJSON() error = %v", err)
+	}
+	if !reflect.DeepEqual(labels, got) {
+		t.Errorf("round trip = %v, want %v", got, labels)
+	}
+}
@@ -226,3 +226,77 @@ func TestLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestLabelScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		match   []string
+		noMatch []string
+		wantErr bool
+	}{
+		{
+			name:    "Glob",
+			input:   "team-a/*",
+			match:   []string{"team-a/bug", "Team-A/Bug", "team-a/"},
+			noMatch: []string{"team-b/bug", "my-team-a/bug", "team-a", "team-a/area/api"},
+		},
+		{
+			name:    "Glob with a character class",
+			input:   "team-[ab]/*",
+			match:   []string{"team-a/bug", "TEAM-B/bug"},
+			noMatch: []string{"team-c/bug"},
+		},
+		{
+			name:    "Glob with ? and regular expression characters",
+			input:   "v1.? (old)",
+			match:   []string{"v1.0 (old)"},
+			noMatch: []string{"v110 (old)", "v1.0 old"},
+		},
+		{
+			name:    "Regular expression",
+			input:   "/^team-(a|b)//",
+			match:   []string{"team-a/bug", "TEAM-B/bug"},
+			noMatch: []string{"team-c/bug", "x-team-a/bug"},
+		},
+		{
+			name:    "Invalid glob",
+			input:   "team-[a",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid regular expression",
+			input:   "/team-(a/",
+			wantErr: true,
+		},
+		{
+			name:    "Empty regular expression",
+			input:   "//",
+			wantErr: true,
+		},
+		{
+			name:    "Empty",
+			input:   " ",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LabelScope(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LabelScope() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			for _, name := range tt.match {
+				if !got.Match(option.Label{Name: name}) {
+					t.Errorf("LabelScope(%q) does not match %q", tt.input, name)
+				}
+			}
+			for _, name := range tt.noMatch {
+				if got.Match(option.Label{Name: name}) {
+					t.Errorf("LabelScope(%q) matches %q", tt.input, name)
+				}
+			}
+		})
+	}
+}
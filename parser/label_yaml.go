@@ -24,7 +24,7 @@ package parser
 
 import (
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/tnagatomi/gh-fuda/option"
 	"gopkg.in/yaml.v3"
@@ -32,23 +32,112 @@ import (
 
 // YAMLLabel represents the YAML structure for a label
 type YAMLLabel struct {
-	Name        string `yaml:"name"`
-	Color       string `yaml:"color"`
-	Description string `yaml:"description"`
+	Name        string   `yaml:"name"`
+	Color       string   `yaml:"color"`
+	Description string   `yaml:"description"`
+	Aliases     []string `yaml:"aliases,omitempty"`
 }
 
-// LabelFromYAML parses labels from a YAML file
+// YAMLLabelGroup represents the YAML structure for a group of labels sharing a
+// name prefix, a color and a description template. Labels are listed by name,
+// or as labels whose fields override those of the group.
+type YAMLLabelGroup struct {
+	Group string `yaml:"group"`
+	// Separator goes between the group name and label names, "/" by default
+	Separator string `yaml:"separator"`
+	Color     string `yaml:"color"`
+	// Description may refer to the label name as {{name}} and to the group
+	// name as {{group}}
+	Description string      `yaml:"description"`
+	Labels      []yaml.Node `yaml:"labels"`
+}
+
+// LabelFromYAML parses labels from a YAML file, either a list of labels or an
+// object composing labels of other files (see labelFile)
 func LabelFromYAML(path string) ([]option.Label, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read YAML file: %v", err)
+	return labelsFromFile(path, labelFormatYAML)
+}
+
+// decodeYAMLLabels decodes a list of labels and label groups, expanding the
+// groups. It returns the labels, the group of each one and the line it is
+// defined at.
+func decodeYAMLLabels(nodes []*yaml.Node) ([]YAMLLabel, []*option.LabelGroup, []int, error) {
+	var labels []YAMLLabel
+	var groups []*option.LabelGroup
+	var lines []int
+
+	for _, node := range nodes {
+		if !isGroupNode(node) {
+			var yl YAMLLabel
+			if err := node.Decode(&yl); err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to parse YAML: %v", err)
+			}
+			labels = append(labels, yl)
+			groups = append(groups, nil)
+			lines = append(lines, node.Line)
+			continue
+		}
+
+		var yg YAMLLabelGroup
+		if err := node.Decode(&yg); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to parse YAML: %v", err)
+		}
+		if yg.Group == "" {
+			return nil, nil, nil, fmt.Errorf("label group at line %d has empty name", node.Line)
+		}
+		group := &option.LabelGroup{Name: yg.Group, Separator: yg.Separator}
+		if group.Separator == "" {
+			group.Separator = option.DefaultGroupSeparator
+		}
+
+		for _, item := range yg.Labels {
+			var yl YAMLLabel
+			var err error
+			if item.Kind == yaml.ScalarNode {
+				yl.Name = item.Value
+			} else {
+				err = item.Decode(&yl)
+			}
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to parse YAML: %v", err)
+			}
+			if yl.Name == "" {
+				return nil, nil, nil, fmt.Errorf("label of group %q at line %d has empty name", yg.Group, item.Line)
+			}
+
+			if yl.Color == "" {
+				yl.Color = yg.Color
+			}
+			if yl.Description == "" {
+				yl.Description = strings.NewReplacer("{{name}}", yl.Name, "{{group}}", yg.Group).Replace(yg.Description)
+			}
+			yl.Name = group.Prefix() + yl.Name
+
+			labels = append(labels, yl)
+			groups = append(groups, group)
+			lines = append(lines, item.Line)
+		}
 	}
 
-	var yamlLabels []YAMLLabel
-	if err := yaml.Unmarshal(data, &yamlLabels); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %v", err)
+	return labels, groups, lines, nil
+}
+
+// isGroupNode returns true if the node of a label list is a label group
+func isGroupNode(node *yaml.Node) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == "group" {
+			return true
+		}
 	}
+	return false
+}
 
+// yamlToLabels validates labels read from YAML. Missing colors are generated
+// if generateColors is set, and left empty otherwise.
+func yamlToLabels(yamlLabels []YAMLLabel, generateColors bool) ([]option.Label, error) {
 	labels := make([]option.Label, 0, len(yamlLabels))
 	for i, yl := range yamlLabels {
 		if yl.Name == "" {
@@ -60,16 +149,51 @@ func LabelFromYAML(path string) ([]option.Label, error) {
 		if color != "" && !isHexColor(color) {
 			return nil, fmt.Errorf("label %q has invalid color format: %s", yl.Name, color)
 		}
-		if color == "" {
+		if color == "" && generateColors {
 			color = GenerateColor(yl.Name)
 		}
 
+		for _, alias := range yl.Aliases {
+			if alias == "" {
+				return nil, fmt.Errorf("label %q has an empty alias", yl.Name)
+			}
+		}
+
 		labels = append(labels, option.Label{
 			Name:        yl.Name,
 			Color:       color,
 			Description: yl.Description,
+			Aliases:     yl.Aliases,
 		})
 	}
 
 	return labels, nil
 }
+
+// LabelToYAML formats labels in the format read by LabelFromYAML. A non-empty
+// comments[i] is written as a comment above the i-th label; comments may be
+// nil.
+func LabelToYAML(labels []option.Label, comments []string) ([]byte, error) {
+	seq := &yaml.Node{Kind: yaml.SequenceNode}
+	for i, label := range labels {
+		node := &yaml.Node{}
+		if err := node.Encode(YAMLLabel{
+			Name:        label.Name,
+			Color:       label.Color,
+			Description: label.Description,
+			Aliases:     label.Aliases,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to format YAML: %v", err)
+		}
+		if i < len(comments) {
+			node.HeadComment = comments[i]
+		}
+		seq.Content = append(seq.Content, node)
+	}
+
+	data, err := yaml.Marshal(seq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format YAML: %v", err)
+	}
+	return data, nil
+}
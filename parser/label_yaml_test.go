@@ -133,6 +133,65 @@ func TestLabelFromYAML(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "label with aliases",
+			yamlContent: `- name: "kind: bug"
+  color: d73a4a
+  aliases:
+    - type/bug
+    - bug`,
+			want: []option.Label{
+				{Name: "kind: bug", Color: "d73a4a", Aliases: []string{"type/bug", "bug"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "label with empty alias",
+			yamlContent: `- name: bug
+  color: d73a4a
+  aliases: [""]`,
+			wantErr:     true,
+			errContains: "has an empty alias",
+		},
+		{
+			name: "label group",
+			yamlContent: `- name: bug
+  color: d73a4a
+- group: area
+  color: 1d76db
+  description: "Area: {{name}}"
+  labels:
+    - api
+    - name: ui
+      color: c5def5
+      aliases: [frontend]
+    - name: docs
+      description: Documentation`,
+			want: []option.Label{
+				{Name: "bug", Color: "d73a4a"},
+				{Name: "area/api", Color: "1d76db", Description: "Area: api", Group: &option.LabelGroup{Name: "area", Separator: "/"}},
+				{Name: "area/ui", Color: "c5def5", Description: "Area: ui", Aliases: []string{"frontend"}, Group: &option.LabelGroup{Name: "area", Separator: "/"}},
+				{Name: "area/docs", Color: "1d76db", Description: "Documentation", Group: &option.LabelGroup{Name: "area", Separator: "/"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "label group with separator and no color",
+			yamlContent: `- group: priority
+  separator: ": "
+  labels: [high]`,
+			want: []option.Label{
+				{Name: "priority: high", Color: GenerateColor("priority: high"), Group: &option.LabelGroup{Name: "priority", Separator: ": "}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "label group with empty name",
+			yamlContent: `- group: ""
+  labels: [api]`,
+			wantErr:     true,
+			errContains: "label group at line 1 has empty name",
+		},
 		{
 			name: "label with invalid hex color",
 			yamlContent: `- name: bug
@@ -193,3 +252,31 @@ func TestLabelFromYAML_FileReadError(t *testing.T) {
 		t.Errorf("LabelFromYAML() error = %v, want error containing 'failed to read YAML file'", err)
 	}
 }
+
+func TestLabelToYAML_RoundTrip(t *testing.T) {
+	labels := []option.Label{
+		{Name: "kind: bug", Color: "d73a4a", Description: "Something isn't working", Aliases: []string{"bug"}},
+		{Name: "black", Color: "000000"},
+		{Name: "123", Color: "123456", Description: "# not a comment"},
+	}
+
+	data, err := LabelToYAML(labels, []string{"conflict: a", "", ""})
+	if err != nil {
+		t.Fatalf("LabelToYAML() error = %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# conflict: a\n") {
+		t.Errorf("LabelToYAML() = %q, want comment above the first label", data)
+	}
+
+	path := filepath.Join(t.TempDir(), "labels.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	got, err := LabelFromYAML(path)
+	if err != nil {
+		t.Fatalf("LabelFromYAML() error = %v", err)
+	}
+	if diff := cmp.Diff(labels, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -23,27 +23,184 @@ THE SOFTWARE.
 package parser
 
 import (
+	"bufio"
 	"fmt"
-	"github.com/tnagatomi/gh-fuda/option"
+	"io"
+	"net/url"
+	"slices"
 	"strings"
+
+	"github.com/tnagatomi/gh-fuda/option"
 )
 
+// Repo parses a comma-separated list of repositories. Each one is either
+// OWNER/REPO, OWNER/* for every repository of the owner, either optionally
+// prefixed with a HOST/, or a repository URL such as
+// https://github.com/OWNER/REPO or git@github.com:OWNER/REPO.git.
 func Repo(input string) ([]option.Repo, error) {
 	inputSplit := strings.Split(input, ",")
 
 	var repos []option.Repo
 	for _, repo := range inputSplit {
-		parts := strings.Split(repo, "/")
-
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid repo format: %s", repo)
+		if strings.TrimSpace(repo) == "" {
+			continue
 		}
+		r, err := parseRepo(repo)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, r)
+	}
 
-		repos = append(repos, option.Repo{
-			Owner: parts[0],
-			Repo:  parts[1],
-		})
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("no repositories specified")
 	}
 
 	return repos, nil
 }
+
+// RepoPatterns parses a comma-separated list of OWNER/REPO glob patterns.
+// Patterns without wildcards accept the formats of Repo.
+func RepoPatterns(input string) (option.RepoPatterns, error) {
+	var patterns option.RepoPatterns
+	for _, p := range strings.Split(input, ",") {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		pattern, err := parseRepoPattern(p)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// Visibilities parses repository visibilities, ignoring case, and rejects
+// values that are not one of option.Visibilities
+func Visibilities(values []string) ([]string, error) {
+	var visibilities []string
+	for _, v := range values {
+		visibility := strings.ToLower(strings.TrimSpace(v))
+		if !slices.Contains(option.Visibilities, visibility) {
+			return nil, fmt.Errorf("invalid visibility %q: must be one of %s", v, strings.Join(option.Visibilities, ", "))
+		}
+		visibilities = append(visibilities, visibility)
+	}
+	return visibilities, nil
+}
+
+// RepoLines reads repositories in the formats accepted by Repo, one per line.
+// Blank lines and comments starting with '#' are ignored, and lines prefixed
+// with '!' are returned as exclusion patterns in the format of RepoPatterns.
+func RepoLines(r io.Reader) (repos []option.Repo, excludes option.RepoPatterns, err error) {
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if exclude, ok := strings.CutPrefix(line, "!"); ok {
+			pattern, err := parseRepoPattern(exclude)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %v", lineNum, err)
+			}
+			excludes = append(excludes, pattern)
+			continue
+		}
+
+		repo, err := parseRepo(line)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		repos = append(repos, repo)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read repositories: %v", err)
+	}
+
+	return repos, excludes, nil
+}
+
+// parseRepo parses a single repository, ignoring surrounding whitespace and a
+// .git suffix
+func parseRepo(input string) (option.Repo, error) {
+	repo := strings.TrimSpace(input)
+
+	var host string
+	switch {
+	case strings.Contains(repo, "://"):
+		u, err := url.Parse(repo)
+		if err != nil {
+			return option.Repo{}, fmt.Errorf("invalid repo format: %s", input)
+		}
+		host = u.Host
+		repo = u.Path
+	case strings.HasPrefix(repo, "git@"):
+		h, path, ok := strings.Cut(strings.TrimPrefix(repo, "git@"), ":")
+		if !ok {
+			return option.Repo{}, fmt.Errorf("invalid repo format: %s", input)
+		}
+		host = h
+		repo = path
+	}
+	repo = strings.TrimSuffix(strings.Trim(repo, "/"), ".git")
+
+	parts := strings.Split(repo, "/")
+
+	// HOST/OWNER/REPO, e.g. ghe.example.com/owner/repo
+	if host == "" && len(parts) == 3 && strings.Contains(parts[0], ".") {
+		host = parts[0]
+		parts = parts[1:]
+	}
+
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return option.Repo{}, fmt.Errorf("invalid repo format: %s", input)
+	}
+
+	// Only a whole repository name can be a wildcard, selecting every
+	// repository of the owner
+	if strings.Contains(parts[0], "*") || (strings.Contains(parts[1], "*") && parts[1] != option.RepoWildcard) {
+		return option.Repo{}, fmt.Errorf("invalid repo format: %s (use owner/* to select every repository of an owner)", input)
+	}
+
+	return option.Repo{
+		Host:  normalizeHost(host),
+		Owner: parts[0],
+		Repo:  parts[1],
+	}, nil
+}
+
+// normalizeHost lowercases a hostname and strips a www. prefix, as in
+// https://www.github.com/owner/repo
+func normalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
+
+// parseRepoPattern parses a single OWNER/REPO or HOST/OWNER/REPO glob pattern
+func parseRepoPattern(input string) (string, error) {
+	pattern := strings.TrimSpace(input)
+	if !strings.ContainsAny(pattern, "*?[") {
+		repo, err := parseRepo(pattern)
+		if err != nil {
+			return "", err
+		}
+		return repo.String(), nil
+	}
+
+	parts := strings.Split(pattern, "/")
+	if len(parts) < 2 || len(parts) > 3 || slices.Contains(parts, "") {
+		return "", fmt.Errorf("invalid repo pattern: %s (use OWNER/REPO or HOST/OWNER/REPO with * or ? wildcards)", input)
+	}
+	if err := option.CheckGlob(pattern); err != nil {
+		return "", fmt.Errorf("invalid repo pattern: %s: %v", input, err)
+	}
+	return pattern, nil
+}
@@ -24,6 +24,7 @@ package parser
 import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/tnagatomi/gh-fuda/option"
+	"strings"
 	"testing"
 )
 
@@ -74,6 +75,95 @@ func TestRepo(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Owner wildcard",
+			args: args{
+				input: "tnagatomi/*,tnagatomi/repo1",
+			},
+			want: []option.Repo{
+				{
+					Owner: "tnagatomi",
+					Repo:  "*",
+				},
+				{
+					Owner: "tnagatomi",
+					Repo:  "repo1",
+				},
+			},
+		},
+		{
+			name: "Wildcard owner",
+			args: args{
+				input: "*/repo1",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Partial wildcard",
+			args: args{
+				input: "tnagatomi/repo*",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Surrounding whitespace",
+			args: args{
+				input: " tnagatomi/repo1 , tnagatomi/repo2 ",
+			},
+			want: []option.Repo{
+				{
+					Owner: "tnagatomi",
+					Repo:  "repo1",
+				},
+				{
+					Owner: "tnagatomi",
+					Repo:  "repo2",
+				},
+			},
+		},
+		{
+			name: "URLs and .git suffixes",
+			args: args{
+				input: "https://github.com/tnagatomi/repo1,https://github.com/tnagatomi/repo2.git/,git@github.com:tnagatomi/repo3.git,github.com/tnagatomi/repo4,tnagatomi/repo5.git",
+			},
+			want: []option.Repo{
+				{Host: "github.com", Owner: "tnagatomi", Repo: "repo1"},
+				{Host: "github.com", Owner: "tnagatomi", Repo: "repo2"},
+				{Host: "github.com", Owner: "tnagatomi", Repo: "repo3"},
+				{Host: "github.com", Owner: "tnagatomi", Repo: "repo4"},
+				{Owner: "tnagatomi", Repo: "repo5"},
+			},
+		},
+		{
+			name: "Hosts",
+			args: args{
+				input: "GHE.example.com/tnagatomi/repo1,ghe.example.com/tnagatomi/*,https://www.github.com/tnagatomi/repo2,git@ghe.example.com:tnagatomi/repo3.git",
+			},
+			want: []option.Repo{
+				{Host: "ghe.example.com", Owner: "tnagatomi", Repo: "repo1"},
+				{Host: "ghe.example.com", Owner: "tnagatomi", Repo: "*"},
+				{Host: "github.com", Owner: "tnagatomi", Repo: "repo2"},
+				{Host: "ghe.example.com", Owner: "tnagatomi", Repo: "repo3"},
+			},
+		},
+		{
+			name: "URL of a page in the repository",
+			args: args{
+				input: "https://github.com/tnagatomi/repo1/issues",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Empty",
+			args: args{
+				input: " , ",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -88,3 +178,116 @@ func TestRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoLines(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantRepos    []option.Repo
+		wantExcludes option.RepoPatterns
+		wantErr      bool
+	}{
+		{
+			name:  "Comments, blank lines and exclusions",
+			input: "# backend services\ntnagatomi/*\n\n  https://github.com/other/repo1  # shared\n!tnagatomi/legacy\n! tnagatomi/sandbox-*\n",
+			wantRepos: []option.Repo{
+				{Owner: "tnagatomi", Repo: "*"},
+				{Host: "github.com", Owner: "other", Repo: "repo1"},
+			},
+			wantExcludes: option.RepoPatterns{"tnagatomi/legacy", "tnagatomi/sandbox-*"},
+		},
+		{
+			name:  "Empty",
+			input: "# nothing yet\n",
+		},
+		{
+			name:    "Invalid line",
+			input:   "tnagatomi/repo1\ntnagatomi\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, excludes, err := RepoLines(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RepoLines() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(repos, tt.wantRepos); diff != "" {
+				t.Errorf("RepoLines() repos mismatch (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(excludes, tt.wantExcludes); diff != "" {
+				t.Errorf("RepoLines() excludes mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRepoPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    option.RepoPatterns
+		wantErr bool
+	}{
+		{
+			name:  "Repositories and globs",
+			input: "tnagatomi/legacy-*, */sandbox,https://github.com/tnagatomi/repo1.git,ghe.example.com/*/sandbox-*",
+			want:  option.RepoPatterns{"tnagatomi/legacy-*", "*/sandbox", "github.com/tnagatomi/repo1", "ghe.example.com/*/sandbox-*"},
+		},
+		{
+			name:    "Glob without owner",
+			input:   "legacy-*",
+			wantErr: true,
+		},
+		{
+			name:    "Malformed glob",
+			input:   "tnagatomi/legacy-[",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RepoPatterns(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RepoPatterns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("RepoPatterns() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVisibilities(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "Any case",
+			input: []string{"Private", " INTERNAL", "public"},
+			want:  []string{"private", "internal", "public"},
+		},
+		{
+			name:    "Unknown visibility",
+			input:   []string{"private", "privte"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Visibilities(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Visibilities() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Visibilities() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}